@@ -1,17 +1,22 @@
 package app
 
 import (
-	"strings"
+	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/bookmarks"
 	"github.com/KonstantinGasser/scotty/app/component/browsing"
 	"github.com/KonstantinGasser/scotty/app/component/docs"
 	"github.com/KonstantinGasser/scotty/app/component/info"
 	"github.com/KonstantinGasser/scotty/app/component/querying"
 	"github.com/KonstantinGasser/scotty/app/component/tailing"
+	"github.com/KonstantinGasser/scotty/app/component/tracing"
 	"github.com/KonstantinGasser/scotty/app/component/welcome"
 	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/config"
 	"github.com/KonstantinGasser/scotty/store"
 	"github.com/KonstantinGasser/scotty/stream"
 	"github.com/charmbracelet/bubbles/key"
@@ -26,7 +31,10 @@ const (
 	tabQuery
 	tabDocs
 
-	whitespace = " "
+	// beams which can be recolored using SPC c [1-9]
+	maxRecolor = 9
+	// tabs which can be switched to by their number
+	maxTabs = 9
 )
 
 type mode struct {
@@ -37,6 +45,9 @@ type mode struct {
 
 type streamConfig struct {
 	color lipgloss.Color
+	// derived is true if the color is taken from the
+	// palette of the theme and thus changes with the theme
+	derived bool
 }
 
 type App struct {
@@ -51,12 +62,27 @@ type App struct {
 	// and App is initialized
 	ready bool
 	// key bindings
-	bindings       *bindings.Map
+	bindings *bindings.Map
+	// recolorNode holds the options to recolor a beam
+	// which are described with the beam's label
+	recolorNode *bindings.Node
+	// mouse is true while mouse events are reported
+	mouse          bool
 	ignoreBindings []key.Binding
 	/* stream / i/o properties */
 	// channels to consume stream events
 	consumer   stream.Consumer
 	subscriber map[string]streamConfig
+	// colors configured for a beam label. Beams without
+	// a configured color get a random color assigned
+	beamColors map[string]lipgloss.Color
+	// labels of all beams in the order they
+	// first connected (same order as in the footer)
+	labels []string
+	// refresh and columns of the pagers
+	// created for beam panes
+	refresh time.Duration
+	columns []string
 
 	// place where all logs are written
 	// to. App manly uses it for inserts
@@ -70,93 +96,201 @@ type App struct {
 	// indication which tab is currently
 	// active and thereby which component
 	activeTab int
+	// split divides the content into panes showing the
+	// components of the keys in panes at the same time.
+	// The active tab is the component of the focused pane.
+	split styles.Split
+	panes []int
+	focus int
+	// beamPanes maps the keys of the components
+	// created per beam to the beam's label
+	beamPanes map[int]string
+	// tabs are the keys of the components
+	// in the order of the tab bar
+	tabs []int
+	// nextKey is the key of the next component
+	// created for a beam pane or a query tab
+	nextKey int
 
 	/* component specific properties */
 	footerComponent tea.Model
+	// bookmarkPanel lists all bookmarks on
+	// top of the content while open
+	bookmarkPanel *bookmarks.Model
 	// map of all available components mapped to
 	// the available tabs
 	components map[int]tea.Model
-
-	// labelMaxIndent is the length of the longest
-	// beam label currently connected and has effect on
-	// the prefix of a line to align the data part in the same
-	// terminal column
-	labelMaxIndent uint8
 }
 
-func New(q chan<- struct{}, refresh time.Duration, lStore *store.Store, consumer stream.Consumer) *App {
+func New(q chan<- struct{}, cfg config.Settings, lStore *store.Store, consumer stream.Consumer) *App {
+
+	refresh := time.Duration(cfg.Refresh)
+
+	beamColors := make(map[string]lipgloss.Color, len(cfg.Beams))
+	for label, beam := range cfg.Beams {
+		if beam.Color != "" {
+			beamColors[label] = lipgloss.Color(beam.Color)
+		}
+		// formats are validated while loading the config
+		_ = lStore.FormatPayloadAs(label, beam.Format)
+	}
+
+	// patterns are validated while loading the config
+	for _, hl := range cfg.Highlights {
+		lStore.Highlight(regexp.MustCompile(hl.Pattern), lipgloss.Color(hl.Color))
+	}
 
 	app := &App{
 		quit:      q,
 		ttyWidth:  -1, // unset/invalid
 		ttyHeight: -1, // unset/invalid
 		ready:     false,
-		bindings:  bindings.NewMap(),
+		bindings:  bindings.NewMap().WithTimeout(time.Duration(cfg.SequenceTimeout)),
+		mouse:     config.Enabled(cfg.Mouse),
 
 		consumer:   consumer,
 		subscriber: make(map[string]streamConfig),
+		beamColors: beamColors,
 		logstore:   lStore,
+		refresh:    refresh,
+		columns:    cfg.Columns,
 
-		headerComponent: nil,
+		headerComponent: styles.NewTabs(0),
 		activeTab:       tabUnset,
+		beamPanes:       make(map[int]string),
+		tabs:            []int{tabFollow, tabBrowse, tabQuery, tabDocs},
+		nextKey:         tabDocs + 1,
 
 		footerComponent: info.New(),
+		bookmarkPanel:   bookmarks.New(lStore),
 		components: map[int]tea.Model{
-			tabFollow: tailing.New(lStore.NewPager(0, 0, refresh)),
-			tabBrowse: browsing.New(lStore.NewFormatter(0, 0)),
-			tabQuery:  querying.New(),
+			tabFollow: tailing.New(lStore.NewPager(0, 0, refresh)).WithColumns(cfg.Columns),
+			tabBrowse: browsing.New(lStore.NewFormatter(0, 0)).WithTraceFields(cfg.TraceFields),
+			tabQuery:  querying.New("query", tailing.New(lStore.NewPager(0, 0, refresh)).WithColumns(cfg.Columns), store.Query{}),
 			tabDocs:   docs.New(),
 		},
 	}
 
-	app.bindings.Bind("ctrl+c").Action(func(msg tea.KeyMsg) tea.Cmd {
+	// filters are validated while loading the config
+	for _, q := range cfg.Queries {
+		query, _ := store.ParseQuery(q.Filter)
+		app.addQueryTab(q.Name, query)
+	}
+
+	quit := func(msg tea.KeyMsg, count int) tea.Cmd {
 		app.quit <- struct{}{}
 		return tea.Quit
-	})
+	}
 
-	app.bindings.Bind(" ").OnESC(func(msg tea.KeyMsg) tea.Cmd {
-		switch app.activeTab {
-		case tabFollow:
-			return info.RequestMode(info.ModeFollowing)
-		case tabBrowse:
-			return info.RequestMode(info.ModeBrowsing)
-		default:
-			return nil
-		}
+	app.bindings.Handle("global.quit", quit)
+
+	app.bindings.OnESC("global.leader", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.modeOfTab()
 	})
 
 	// set quit option here again in order to quit the app while running a
 	// key stroke sequence
-	app.bindings.Bind(" ").Option("ctrl+c").Action(func(msg tea.KeyMsg) tea.Cmd {
-		app.quit <- struct{}{}
-		return tea.Quit
+	app.bindings.Handle("global.leader", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return info.RequestMode(info.ModeGlobalCmd)
+	}).Option("ctrl+c").Describe("quit scotty").Action(quit)
+
+	app.bindings.Handle("global.switch.follow", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.switchTo(tabFollow)
+	})
+
+	app.bindings.Handle("global.switch.browse", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.switchTo(tabBrowse)
+	})
+
+	app.bindings.Handle("global.switch.query", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.switchTo(tabQuery)
+	})
+
+	app.bindings.Handle("global.switch.docs", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.switchTo(tabDocs)
 	})
 
-	app.bindings.Bind(" ").
-		Action(func(msg tea.KeyMsg) tea.Cmd {
-			return info.RequestMode(info.ModeGlobalCmd)
-		}).
-		Option("f").Action(func(msg tea.KeyMsg) tea.Cmd {
-		if app.activeTab == tabFollow {
-			return nil
+	for n := 1; n <= maxTabs; n++ {
+		i := n - 1
+		app.bindings.Handle(fmt.Sprintf("global.tab.%d", n), func(msg tea.KeyMsg, count int) tea.Cmd {
+			if i >= len(app.tabs) {
+				return app.modeOfTab()
+			}
+			return app.switchTo(app.tabs[i])
+		})
+	}
+
+	app.bindings.Handle("global.theme", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return tea.Batch(app.switchTheme(), app.modeOfTab())
+	})
+
+	app.bindings.Handle("global.mouse", func(msg tea.KeyMsg, count int) tea.Cmd {
+		app.mouse = !app.mouse
+		if app.mouse {
+			return tea.Batch(tea.EnableMouseCellMotion, app.modeOfTab())
 		}
-		app.activeTab = tabFollow
-		return info.RequestMode(info.ModeFollowing)
+		return tea.Batch(tea.DisableMouse, app.modeOfTab())
+	})
+
+	app.bindings.Handle("global.split.horizontal", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.splitPanes(styles.SplitHorizontal)
 	})
 
-	app.bindings.Bind(" ").
-		Option("b").Action(func(msg tea.KeyMsg) tea.Cmd {
-		if app.activeTab == tabBrowse {
-			return nil
+	app.bindings.Handle("global.split.vertical", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.splitPanes(styles.SplitVertical)
+	})
+
+	app.bindings.Handle("global.split.beams", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.splitBeams()
+	})
+
+	app.bindings.Handle("global.focus", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if len(app.panes) == 0 {
+			return app.modeOfTab()
 		}
+		return app.focusNext()
+	})
+
+	app.bindings.Handle("global.bookmarks", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return app.bookmarkPanel.Open()
+	})
 
-		app.activeTab = tabBrowse
-		return tea.Batch(info.RequestMode(info.ModeBrowsing), browsing.RequestInitialView)
+	app.recolorNode = app.bindings.Handle("global.recolor", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return info.RequestMode(app.recolorMode())
 	})
 
 	return app
 }
 
+// typing is implemented by components with a prompt. While
+// the prompt is focused keys are not matched against the
+// global bindings but passed to the component.
+type typing interface {
+	Typing() bool
+}
+
+// modeOfTab returns the mode of the currently active tab
+func (app *App) modeOfTab() tea.Cmd {
+	switch app.activeTab {
+	case tabFollow:
+		return info.RequestMode(info.ModeFollowing)
+	case tabBrowse:
+		return info.RequestMode(app.components[tabBrowse].(*browsing.Model).Mode())
+	case tabDocs:
+		return info.RequestMode(info.ModeDocs)
+	}
+	switch app.components[app.activeTab].(type) {
+	case *querying.Model:
+		return info.RequestMode(info.ModeQuery)
+	case *tracing.Model:
+		return info.RequestMode(info.ModeTrace)
+	}
+	if _, ok := app.beamPanes[app.activeTab]; ok {
+		return info.RequestMode(info.ModeFollowing)
+	}
+	return nil
+}
+
 func (app App) Init() tea.Cmd {
 	return tea.Batch(
 		app.consumeMsg,
@@ -167,6 +301,7 @@ func (app App) Init() tea.Cmd {
 }
 
 func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer app.updateTabs()
 
 	var (
 		cmds []tea.Cmd
@@ -179,13 +314,30 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// while no beam has connected and we are in the
 		// welcome screen
 		if app.activeTab == tabUnset {
-			if key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c"))) {
+			if key.Matches(msg, key.NewBinding(key.WithKeys(bindings.Keys("global.quit")...))) {
 				cmds = append(cmds, app.bindings.Exec(msg).Call(msg))
 				return app, tea.Batch(cmds...)
 			}
 			break
 		}
 
+		// while the user types into a prompt the keys
+		// belong to the prompt; only quitting is possible
+		if input, ok := app.components[app.activeTab].(typing); ok && input.Typing() &&
+			!key.Matches(msg, key.NewBinding(key.WithKeys(bindings.Keys("global.quit")...))) {
+			app.components[app.activeTab], cmd = app.components[app.activeTab].Update(msg)
+			cmds = append(cmds, cmd)
+			return app, tea.Batch(cmds...)
+		}
+
+		// while the bookmarks are listed the keys
+		// belong to the panel; only quitting is possible
+		if app.bookmarkPanel.IsOpen() &&
+			!key.Matches(msg, key.NewBinding(key.WithKeys(bindings.Keys("global.quit")...))) {
+			_, cmd = app.bookmarkPanel.Update(msg)
+			return app, cmd
+		}
+
 		if !app.bindings.Matches(msg) {
 			// does not mean the action component
 			// might not do something with the event
@@ -198,6 +350,56 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, app.bindings.Exec(msg).Call(msg))
 		return app, tea.Batch(cmds...)
 
+	// a global key sequence has not been continued in time
+	case bindings.Expired:
+		cmds = append(cmds, app.bindings.Expire(msg).Call(tea.KeyMsg{}))
+		return app, tea.Batch(cmds...)
+
+	// rows of the mouse events are made relative to the
+	// region (content or footer) the event occurred in
+	case tea.MouseMsg:
+		if app.activeTab == tabUnset {
+			break
+		}
+
+		top := app.grid.TabLine.Height()
+		switch {
+		case msg.Y < top && msg.Type == tea.MouseLeft:
+			return app, app.tabAt(msg.X)
+		case msg.Y == app.grid.FooterRow():
+			app.footerComponent, cmd = app.footerComponent.Update(msg)
+		case msg.Y >= top && msg.Y < top+app.grid.Content.Height() && len(app.panes) > 0:
+			msg.Y -= top
+			return app, app.mousePane(msg)
+		case msg.Y >= top && msg.Y < top+app.grid.Content.Height():
+			msg.Y -= top
+			app.components[app.activeTab], cmd = app.components[app.activeTab].Update(msg)
+		}
+		return app, cmd
+
+	// triggered by clicking a beam in the footer
+	case info.MuteRequest:
+		app.logstore.Mute(string(msg))
+		return app, app.filterBeams()
+
+	case info.SoloRequest:
+		app.logstore.Solo(string(msg))
+		return app, app.filterBeams()
+
+	// triggered by clicking a line in the follow view
+	case tailing.BrowseRequest:
+		app.show(tabBrowse)
+		return app, tea.Batch(app.modeOfTab(), browsing.RequestOpen(uint32(msg)))
+
+	// triggered by the bookmarks panel
+	case bookmarks.CloseRequest:
+		return app, app.modeOfTab()
+
+	case bookmarks.RemoveRequest:
+		app.follow(tailing.RequestRebuild()())
+		app.components[tabBrowse], _ = app.components[tabBrowse].Update(browsing.RequestReload())
+		return app, nil
+
 	case tea.WindowSizeMsg:
 
 		// iterate over all components as they are not
@@ -211,10 +413,7 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		app.footerComponent, cmd = app.footerComponent.Update(app.grid.FooterLine.Dims())
 		cmds = append(cmds, cmd)
-		for i, comp := range app.components {
-			app.components[i], cmd = comp.Update(app.grid.Content.Dims())
-			cmds = append(cmds, cmd)
-		}
+		app.layout()
 
 		return app, tea.Batch(cmds...)
 
@@ -231,43 +430,76 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tailing.ResumeRequest:
 		cmds = append(cmds,
 			info.RequestResume(),
-			info.RequestMode(info.ModeFollowing),
+			app.modeOfTab(),
 		)
 
+	// triggered by the query tab to follow a filter in its own tab
+	case querying.SaveRequest:
+		query, err := store.ParseQuery(msg.Filter)
+		if err != nil {
+			break
+		}
+		key := app.addQueryTab(msg.Name, query)
+		app.layout()
+		return app, app.switchTo(key)
+
+	case querying.CloseRequest:
+		return app, app.closeTab(app.activeTab)
+
+	// triggered by the browse view to follow
+	// a request across beams
+	case browsing.TraceRequest:
+		key := app.addTraceTab(msg.Field, msg.Value)
+		app.layout()
+		return app, app.switchTo(key)
+
+	case tracing.CloseRequest:
+		return app, app.closeTab(app.activeTab)
+
 	// triggered each time a new stream connects successfully to scotty and is procssed
-	// by the stream. A random color is assigned to the stream if not yet pressent
-	// (identified by its label). An update about the new stream is propagated to the info
-	// component.
+	// by the stream. If not yet pressent (identified by its label) a color is assigned
+	// to the stream. The configured color wins over the color requested by the beam
+	// which wins over the color derived from the label. An update about the new
+	// stream is propagated to the info component.
 	case stream.Subscriber:
-		if _, ok := app.subscriber[string(msg)]; !ok {
-			fg, _ := styles.RandColor()
-			app.subscriber[string(msg)] = streamConfig{color: fg}
+		if _, ok := app.subscriber[msg.Label]; !ok {
+			fg, ok := app.beamColors[msg.Label]
+			var derived bool
+			if !ok {
+				fg, derived = styles.BeamColor(msg.Label), true
+				if config.ValidColor(msg.Color) {
+					fg, derived = lipgloss.Color(msg.Color), false
+				}
+			}
+			app.subscriber[msg.Label] = streamConfig{color: fg, derived: derived}
+			app.labels = append(app.labels, msg.Label)
+			// beams are recolored by their position
+			// in the footer (SPC c 1-9)
+			if n := len(app.labels) - 1; n < maxRecolor {
+				app.recolorNode.Option(strconv.Itoa(n + 1)).Describe(msg.Label).Action(func(msg tea.KeyMsg, count int) tea.Cmd {
+					return tea.Batch(app.recolor(n), app.modeOfTab())
+				})
+			}
+			app.logstore.SetColor(msg.Label, fg)
+			// a longer label changes the prefix width
+			// of the lines already rendered
+			app.follow(tailing.RequestRebuild()())
 		}
 
 		app.footerComponent, _ = app.footerComponent.Update(
-			info.RequestSubscribe(string(msg), app.subscriber[string(msg)].color)(),
+			info.RequestSubscribe(msg.Label, app.subscriber[msg.Label].color)(),
 		)
-
-		if uint8(len(string(msg))) > app.labelMaxIndent {
-			app.labelMaxIndent = uint8(len(string(msg)))
-		}
+		// a new beam is hidden while another one is solo
+		app.filterBeams()
 
 		cmds = append(cmds, app.consumeSubscriber)
 		return app, tea.Batch(cmds...)
 
 	case stream.Unsubscribe:
-		if app.activeTab == tabFollow {
-			app.components[tabFollow], _ = app.components[tabFollow].Update(tailing.RequestRefresh()())
+		if app.activeTab == tabFollow || len(app.panes) > 0 {
+			app.follow(tailing.RequestRefresh()())
 		}
 
-		if uint8(len(string(msg))) >= app.labelMaxIndent { // this is not perfect; len(msg) = 8 && labelMaxIndent = 8 but len(msg2) = 8 and updated labelMaxIndent last then we do extra work....
-			// find new longest label
-			for label := range app.subscriber {
-				if len(label) >= len(string(msg)) {
-					app.labelMaxIndent = uint8(len(label))
-				}
-			}
-		}
 		app.footerComponent, _ = app.footerComponent.Update(info.RequestUnsubscribe(string(msg))())
 
 		cmds = append(cmds, app.consumeUnsubscribe)
@@ -275,8 +507,8 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// triggered each time a new message is pushed from the stream to
 	// the consumer.
-	// Requires to identify the stream the message is from, build the prefix
-	// and to store the message in the log-store. Furthermore, inserts into
+	// Requires to identify the stream the message is from and to store
+	// the message in the log-store. Furthermore, inserts into
 	// the log-store will happend dispite the active tab. This allows background
 	// updates of the follow-components between tab switches.
 	case stream.Message:
@@ -289,29 +521,35 @@ func (app *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// app.updateActiveTab()
 		}
 
-		config, ok := app.subscriber[msg.Label]
-		if !ok {
+		if _, ok := app.subscriber[msg.Label]; !ok {
 			break
 		}
 
-		indent := clamp(int(app.labelMaxIndent) - len(msg.Label))
-		prefix := lipgloss.NewStyle().Foreground(config.color).Render(msg.Label) + strings.Repeat(whitespace, indent) + " | "
-
-		app.logstore.Insert(msg.Label, len(prefix), append([]byte(prefix), msg.Data...))
-		// update follow component asap in order to allow background updates while
+		app.logstore.Insert(msg.Label, msg.Received, msg.Data)
+		// update follow components asap in order to allow background updates while
 		// in a different tab
-		app.components[tabFollow], _ = app.components[tabFollow].Update(msg)
+		app.follow(msg)
 		cmds = append(cmds, app.consumeMsg)
 
 		app.footerComponent, _ = app.footerComponent.Update(info.RequestIncrement(msg.Label)())
+		app.footerComponent, _ = app.footerComponent.Update(info.RequestMemory(app.logstore.Usage())())
 		return app, tea.Batch(cmds...)
 	}
 
-	// follow component is updates asap after a message is received
+	// follow component is updates asap after a message is received.
+	// Other panes are updated as well to render for example reloads
+	// while not focused
 	if app.activeTab != tabUnset && app.activeTab != tabFollow {
 		app.components[app.activeTab], cmd = app.components[app.activeTab].Update(msg)
 		cmds = append(cmds, cmd)
 	}
+	for _, key := range app.panes {
+		if key == tabFollow || key == app.activeTab {
+			continue
+		}
+		app.components[key], cmd = app.components[key].Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	app.footerComponent, cmd = app.footerComponent.Update(msg)
 	cmds = append(cmds, cmd)
@@ -325,24 +563,100 @@ func (app App) View() string {
 		return welcome.New(app.grid.FullWidth, app.grid.FullHeight).View()
 	}
 
+	content := app.components[app.activeTab].View()
+	if len(app.panes) > 0 {
+		content = app.viewPanes()
+	}
+	if app.bookmarkPanel.IsOpen() {
+		panel := app.bookmarkPanel.View()
+		x := (app.grid.Content.Width() - lipgloss.Width(panel)) / 2
+		y := (app.grid.Content.Height() - lipgloss.Height(panel)) / 2
+		content = styles.Overlay(x, y, panel, content, false)
+	}
+
 	return lipgloss.NewStyle().
 		Render(
 			lipgloss.JoinVertical(lipgloss.Left,
-				app.components[app.activeTab].View(),
+				app.grid.TabLine.Render(app.headerComponent.View()),
+				app.whichKey(content),
 				app.grid.FooterLine.Render(app.footerComponent.View()),
 			),
 		)
 }
 
+// recolor assigns the next palette color to the n-th beam
+// (as shown in the footer). The new color is applied to the
+// footer as well as to the already stored logs of the beam.
+func (app *App) recolor(n int) tea.Cmd {
+	if n < 0 || n >= len(app.labels) {
+		return nil
+	}
+
+	label := app.labels[n]
+	fg := styles.NextColor(app.subscriber[label].color)
+	app.subscriber[label] = streamConfig{color: fg}
+
+	app.logstore.SetColor(label, fg)
+
+	app.footerComponent, _ = app.footerComponent.Update(info.RequestRecolor(label, fg)())
+	app.follow(tailing.RequestRebuild()())
+
+	return browsing.RequestReload
+}
+
+// filterBeams applies muted and solo beams
+// to the follow view and the footer
+func (app *App) filterBeams() tea.Cmd {
+	hidden := make(map[string]bool, len(app.labels))
+	for _, label := range app.labels {
+		hidden[label] = app.logstore.Hidden(label)
+	}
+
+	app.footerComponent, _ = app.footerComponent.Update(info.RequestHidden(hidden)())
+	app.follow(tailing.RequestRebuild()())
+	return nil
+}
+
+// switchTheme activates the next theme. Beams colored from
+// the palette of the previous theme are colored from the palette
+// of the new theme while configured or picked colors are kept.
+func (app *App) switchTheme() tea.Cmd {
+	styles.SetTheme(styles.NextTheme())
+
+	for _, label := range app.labels {
+		beam := app.subscriber[label]
+		if !beam.derived {
+			continue
+		}
+		beam.color = styles.BeamColor(label)
+		app.subscriber[label] = beam
+
+		app.logstore.SetColor(label, beam.color)
+		app.footerComponent, _ = app.footerComponent.Update(info.RequestRecolor(label, beam.color)())
+	}
+
+	app.footerComponent, _ = app.footerComponent.Update(styles.ThemeChanged{})
+	app.components[tabDocs], _ = app.components[tabDocs].Update(styles.ThemeChanged{})
+	app.follow(tailing.RequestRebuild()())
+
+	return browsing.RequestReload
+}
+
+// recolorMode lists the connected beams which can be recolored
+func (app *App) recolorMode() info.AppMode {
+	mode := info.ModeRecolor
+	mode.Opts = nil
+	for i, label := range app.labels {
+		if i >= maxRecolor {
+			break
+		}
+		mode.Opts = append(mode.Opts, fmt.Sprintf(" ·%d %s", i+1, label))
+	}
+	return mode
+}
+
 /* consume* yields back a tea.Msg piped through a channel ending in the app.Update func */
 func (app *App) consumeMsg() tea.Msg         { return <-app.consumer.Messages() }
 func (app *App) consumeErrs() tea.Msg        { return <-app.consumer.Errors() }
 func (app *App) consumeSubscriber() tea.Msg  { return <-app.consumer.Subscribers() }
 func (app *App) consumeUnsubscribe() tea.Msg { return <-app.consumer.Unsubscribers() }
-
-func clamp(a int) int {
-	if a < 0 {
-		return 0
-	}
-	return a
-}
@@ -0,0 +1,521 @@
+package bindings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// leaderName is how the space key is written
+	// in a key sequence
+	leaderName = "SPC"
+)
+
+// Action is a named action components bind a Func to. The
+// keys triggering the action are looked up in the active Keymap.
+type Action struct {
+	// Name of the action; the part before the first
+	// dot is the scope the action is available in
+	Name string
+	// Description is a short label of the action as
+	// shown in the footer and the which-key popup
+	Description string
+	// Help explains the action in the documentation
+	Help string
+	// Prefix marks actions which are meant to be the
+	// beginning of a sequence of other actions (such
+	// as the leader key)
+	Prefix bool
+}
+
+// Scope returns the scope of the action. Actions of the
+// global scope are available in every other scope.
+func (action Action) Scope() string {
+	scope, _, _ := strings.Cut(action.Name, ".")
+	return scope
+}
+
+const globalScope = "global"
+
+// actions are all actions components can bind to in the
+// order they are listed in the documentation
+var actions = []Action{
+	{Name: "global.quit", Description: "quit scotty", Help: "Quit scotty; connected beams are disconnected."},
+	{Name: "global.leader", Description: "start a global command", Help: "Start a global command; the popup lists the keys which can follow.", Prefix: true},
+	{Name: "global.switch.follow", Description: "follow", Help: "Switch to the follow tab which tails the latest logs."},
+	{Name: "global.switch.browse", Description: "browse", Help: "Switch to the browse tab to look at single logs."},
+	{Name: "global.switch.query", Description: "query", Help: "Switch to the query tab which follows the logs matching a filter."},
+	{Name: "global.switch.docs", Description: "docs", Help: "Switch to the docs tab (this page)."},
+	{Name: "global.tab.1", Description: "tab 1", Help: "Switch to the first tab of the tab bar."},
+	{Name: "global.tab.2", Description: "tab 2", Help: "Switch to the second tab of the tab bar."},
+	{Name: "global.tab.3", Description: "tab 3", Help: "Switch to the third tab of the tab bar."},
+	{Name: "global.tab.4", Description: "tab 4", Help: "Switch to the fourth tab of the tab bar."},
+	{Name: "global.tab.5", Description: "tab 5", Help: "Switch to the fifth tab of the tab bar."},
+	{Name: "global.tab.6", Description: "tab 6", Help: "Switch to the sixth tab of the tab bar."},
+	{Name: "global.tab.7", Description: "tab 7", Help: "Switch to the seventh tab of the tab bar."},
+	{Name: "global.tab.8", Description: "tab 8", Help: "Switch to the eighth tab of the tab bar."},
+	{Name: "global.tab.9", Description: "tab 9", Help: "Switch to the ninth tab of the tab bar."},
+	{Name: "global.recolor", Description: "recolor", Help: "Assign the next color to a beam; followed by the beam's position in the footer.", Prefix: true},
+	{Name: "global.theme", Description: "theme", Help: "Switch to the next color theme."},
+	{Name: "global.mouse", Description: "mouse", Help: "Turn mouse support on or off; while off the terminal's own text selection works."},
+	{Name: "global.split.horizontal", Description: "split below", Help: "Split the content into the active tab on top and a second tab below (browse below follow); again to show one pane."},
+	{Name: "global.split.vertical", Description: "split right", Help: "Split the content into the active tab on the left and a second tab on the right; again to show one pane."},
+	{Name: "global.split.beams", Description: "pane per beam", Help: "Follow each connected beam in its own pane (up to 4); again to show the follow tab only."},
+	{Name: "global.focus", Description: "next pane", Help: "Move the focus to the next pane; keys go to the focused pane."},
+	{Name: "global.bookmarks", Description: "bookmarks", Help: "Open the list of all bookmarks; enter browses the selected bookmark."},
+	{Name: "follow.pause", Description: "pause/continue", Help: "Pause the view while logs are still received in the background; press again to continue."},
+	{Name: "follow.latest", Description: "go to latest", Help: "Show the latest logs while the view is paused."},
+	{Name: "follow.jump", Description: "jump", Help: "Jump to an index, a time (hh:mm:ss) or a duration ago (30s); confirm with enter."},
+	{Name: "follow.time", Description: "time", Help: "Cycle the time shown in front of each log: off, absolute, relative."},
+	{Name: "follow.table", Description: "table", Help: "Toggle between the logs as lines and a table of the picked columns."},
+	{Name: "follow.columns", Description: "columns", Help: "Pick the fields shown as columns of the table (level, msg, user_id); confirm with enter."},
+	{Name: "follow.sort", Description: "sort", Help: "Sort all buffered logs of the table by a column (-column descending, empty unsorts); pauses the view."},
+	{Name: "follow.mark", Description: "bookmark", Help: "Bookmark the latest log of the page with an optional note (or remove its bookmark); takes a count to bookmark an earlier log (3m)."},
+	{Name: "follow.index", Description: "index", Help: "Show or hide the index of each log in front of it (the gutter); the index can be typed into the jump and browse prompts."},
+	{Name: "follow.down", Description: "cursor down", Help: "Move the cursor to the next log; the first move shows the cursor on the latest log and pauses the view. Takes a count."},
+	{Name: "follow.up", Description: "cursor up", Help: "Move the cursor to the previous log; the first move shows the cursor on the latest log and pauses the view. Takes a count."},
+	{Name: "follow.browse", Description: "browse", Help: "Open the log under the cursor (the latest log without cursor) in the browse view."},
+	{Name: "follow.wrap", Description: "wrap", Help: "Turn wrapping of long lines on or off; without wrapping each log takes one line which is cut at the edge."},
+	{Name: "follow.left", Description: "scroll left", Help: "Scroll the logs to the left while the label stays in place; takes a count."},
+	{Name: "follow.right", Description: "scroll right", Help: "Scroll the logs to the right while the label stays in place; turns wrapping off. Takes a count."},
+	{Name: "browse.next", Description: "next", Help: "Select the next log; takes a count (50j)."},
+	{Name: "browse.previous", Description: "previous", Help: "Select the previous log; takes a count (10k)."},
+	{Name: "browse.reload", Description: "reload", Help: "Reload the page with the latest data of the buffer."},
+	{Name: "browse.jump", Description: "jump", Help: "Jump to an index, a time (hh:mm:ss) or a duration ago (30s); confirm with enter."},
+	{Name: "browse.time", Description: "time", Help: "Cycle the time shown in front of each log: off, absolute, relative."},
+	{Name: "browse.index", Description: "index", Help: "Show or hide the index of each log in front of it (the gutter)."},
+	{Name: "browse.wrap", Description: "wrap", Help: "Turn wrapping of the log in the modal on or off; without wrapping long lines are cut at the edge."},
+	{Name: "browse.left", Description: "scroll left", Help: "Scroll the modal and the logs behind it to the left while the label stays in place; takes a count."},
+	{Name: "browse.right", Description: "scroll right", Help: "Scroll the modal and the logs behind it to the right while the label stays in place; turns wrapping off. Takes a count."},
+	{Name: "browse.copy", Description: "copy", Help: "Start copying the selected log to the clipboard; the next key picks what is copied.", Prefix: true},
+	{Name: "browse.copy.raw", Description: "copy log", Help: "Copy the selected log as received; takes a count to copy the following logs as well (5yy)."},
+	{Name: "browse.copy.pretty", Description: "copy pretty", Help: "Copy the selected log as shown in the modal (indented JSON)."},
+	{Name: "browse.copy.field", Description: "copy field", Help: "Copy the value of a field of the selected log (user.roles.0); confirm with enter."},
+	{Name: "browse.copy.range", Description: "copy range", Help: "Copy the logs between two indexes (120-180); confirm with enter."},
+	{Name: "browse.copy.path", Description: "copy path", Help: "Copy the JSONPath ($.user.roles[0]) of the node selected in the tree."},
+	{Name: "browse.diff", Description: "diff", Help: "Mark the selected log, then select another log and diff them: changed, added and removed keys of JSON logs or else the changed lines; again to close the diff."},
+	{Name: "browse.mark", Description: "bookmark", Help: "Bookmark the selected log with an optional note (or remove its bookmark)."},
+	{Name: "browse.bookmark.next", Description: "next bookmark", Help: "Select the next bookmarked log."},
+	{Name: "browse.bookmark.previous", Description: "previous bookmark", Help: "Select the previous bookmarked log."},
+	{Name: "browse.trace", Description: "trace", Help: "Follow the request of the selected log across beams: all logs with the same trace_id, request_id or correlation_id (or the value selected in the tree) in a tab of their own."},
+	{Name: "browse.tree", Description: "tree", Help: "Explore the selected JSON log as a tree of collapsible objects and arrays; again to close the tree."},
+	{Name: "browse.tree.down", Description: "down", Help: "Select the next node of the tree; takes a count."},
+	{Name: "browse.tree.up", Description: "up", Help: "Select the previous node of the tree; takes a count."},
+	{Name: "browse.tree.pagedown", Description: "page down", Help: "Scroll the tree down by a page."},
+	{Name: "browse.tree.pageup", Description: "page up", Help: "Scroll the tree up by a page."},
+	{Name: "browse.tree.toggle", Description: "expand/collapse", Help: "Expand or collapse the selected object or array."},
+	{Name: "browse.tree.expand", Description: "expand all", Help: "Expand all objects and arrays below the selected node."},
+	{Name: "browse.tree.collapse", Description: "collapse all", Help: "Collapse all objects and arrays below the selected node."},
+	{Name: "browse.tree.find", Description: "find key", Help: "Select the next node whose key contains the text; confirm with enter."},
+	{Name: "browse.tree.pin", Description: "pin", Help: "Pin the selected node (or unpin it); pinned nodes stay expanded while moving through the logs with next and previous."},
+	{Name: "query.filter", Description: "filter", Help: "Type the filter of the query tab (level=error latency>200 msg~timeout); confirm with enter."},
+	{Name: "query.save", Description: "save as tab", Help: "Open a new tab following the logs of the current filter; its unread matches are counted in the tab bar."},
+	{Name: "query.close", Description: "close tab", Help: "Close the tab of a saved filter."},
+	{Name: "trace.down", Description: "down", Help: "Select the next log of the trace; takes a count."},
+	{Name: "trace.up", Description: "up", Help: "Select the previous log of the trace; takes a count."},
+	{Name: "trace.open", Description: "browse", Help: "Browse the selected log of the trace."},
+	{Name: "trace.close", Description: "close tab", Help: "Close the tab of the trace."},
+	{Name: "docs.search", Description: "search", Help: "Search the key bindings; leave the search with esc."},
+	{Name: "bookmarks.down", Description: "down", Help: "Select the next bookmark of the list."},
+	{Name: "bookmarks.up", Description: "up", Help: "Select the previous bookmark of the list."},
+	{Name: "bookmarks.open", Description: "browse", Help: "Browse the selected bookmark; bookmarks of logs the buffer overwrote cannot be browsed."},
+	{Name: "bookmarks.remove", Description: "remove", Help: "Remove the selected bookmark."},
+	{Name: "bookmarks.close", Description: "close", Help: "Close the list of bookmarks (or esc)."},
+}
+
+// Actions returns all actions which can be bound
+func Actions() []Action {
+	return append([]Action(nil), actions...)
+}
+
+// Lookup returns the action with the name
+func Lookup(name string) (Action, bool) {
+	for _, action := range actions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// Keymap maps the name of an action to the key or key sequence
+// triggering it. Keys of a sequence are separated by spaces and
+// the space key is written as SPC (e.g. "SPC f").
+type Keymap map[string]string
+
+var defaultKeymap = Keymap{
+	"global.quit":              "ctrl+c",
+	"global.leader":            "SPC",
+	"global.switch.follow":     "SPC f",
+	"global.switch.browse":     "SPC b",
+	"global.switch.query":      "SPC q",
+	"global.switch.docs":       "SPC d",
+	"global.tab.1":             "SPC 1",
+	"global.tab.2":             "SPC 2",
+	"global.tab.3":             "SPC 3",
+	"global.tab.4":             "SPC 4",
+	"global.tab.5":             "SPC 5",
+	"global.tab.6":             "SPC 6",
+	"global.tab.7":             "SPC 7",
+	"global.tab.8":             "SPC 8",
+	"global.tab.9":             "SPC 9",
+	"global.recolor":           "SPC c",
+	"global.theme":             "SPC t",
+	"global.mouse":             "SPC m",
+	"global.split.horizontal":  "SPC -",
+	"global.split.vertical":    "SPC |",
+	"global.split.beams":       "SPC =",
+	"global.focus":             "SPC w",
+	"global.bookmarks":         "SPC '",
+	"follow.pause":             "p",
+	"follow.latest":            "g",
+	"follow.jump":              ":",
+	"follow.time":              "t",
+	"follow.table":             "T",
+	"follow.columns":           "C",
+	"follow.sort":              "S",
+	"follow.mark":              "m",
+	"follow.index":             "#",
+	"follow.down":              "down",
+	"follow.up":                "up",
+	"follow.browse":            "o",
+	"follow.wrap":              "w",
+	"follow.left":              "h",
+	"follow.right":             "l",
+	"browse.next":              "j",
+	"browse.previous":          "k",
+	"browse.reload":            "r",
+	"browse.jump":              ":",
+	"browse.time":              "t",
+	"browse.index":             "#",
+	"browse.wrap":              "w",
+	"browse.left":              "h",
+	"browse.right":             "l",
+	"browse.copy":              "y",
+	"browse.copy.raw":          "y y",
+	"browse.copy.pretty":       "y p",
+	"browse.copy.field":        "y f",
+	"browse.copy.range":        "y r",
+	"browse.copy.path":         "y j",
+	"browse.diff":              "d",
+	"browse.mark":              "m",
+	"browse.bookmark.next":     "]",
+	"browse.bookmark.previous": "[",
+	"browse.trace":             "c",
+	"browse.tree":              "e",
+	"browse.tree.down":         "down",
+	"browse.tree.up":           "up",
+	"browse.tree.pagedown":     "pgdown",
+	"browse.tree.pageup":       "pgup",
+	"browse.tree.toggle":       "tab",
+	"browse.tree.expand":       "+",
+	"browse.tree.collapse":     "-",
+	"browse.tree.find":         "/",
+	"browse.tree.pin":          "p",
+	"query.filter":             "/",
+	"query.save":               "n",
+	"query.close":              "x",
+	"trace.down":               "j",
+	"trace.up":                 "k",
+	"trace.open":               "enter",
+	"trace.close":              "x",
+	"docs.search":              "/",
+	"bookmarks.down":           "j",
+	"bookmarks.up":             "k",
+	"bookmarks.open":           "enter",
+	"bookmarks.remove":         "x",
+	"bookmarks.close":          "q",
+}
+
+// Presets are the keymaps shipped with scotty. Any preset
+// can be adjusted with the keys of the config file.
+var Presets = map[string]Keymap{
+	"default": defaultKeymap,
+	"vim": defaultKeymap.with(Keymap{
+		"follow.latest": "G",
+		"follow.down":   "j",
+		"follow.up":     "k",
+		"browse.reload": "ctrl+l",
+	}),
+	"emacs": defaultKeymap.with(Keymap{
+		"global.leader":            "ctrl+x",
+		"global.switch.follow":     "ctrl+x f",
+		"global.switch.browse":     "ctrl+x b",
+		"global.switch.query":      "ctrl+x q",
+		"global.switch.docs":       "ctrl+x d",
+		"global.tab.1":             "alt+1",
+		"global.tab.2":             "alt+2",
+		"global.tab.3":             "alt+3",
+		"global.tab.4":             "alt+4",
+		"global.tab.5":             "alt+5",
+		"global.tab.6":             "alt+6",
+		"global.tab.7":             "alt+7",
+		"global.tab.8":             "alt+8",
+		"global.tab.9":             "alt+9",
+		"global.recolor":           "ctrl+x c",
+		"global.theme":             "ctrl+x t",
+		"global.mouse":             "ctrl+x m",
+		"global.split.horizontal":  "ctrl+x 2",
+		"global.split.vertical":    "ctrl+x 3",
+		"global.split.beams":       "ctrl+x 4",
+		"global.focus":             "ctrl+x o",
+		"global.bookmarks":         "ctrl+x r",
+		"follow.pause":             "ctrl+s",
+		"follow.latest":            "alt+>",
+		"follow.jump":              "alt+g",
+		"follow.time":              "alt+t",
+		"follow.table":             "alt+v",
+		"follow.columns":           "alt+c",
+		"follow.sort":              "alt+s",
+		"follow.mark":              "alt+m",
+		"follow.index":             "alt+i",
+		"follow.down":              "ctrl+n",
+		"follow.up":                "ctrl+p",
+		"follow.browse":            "alt+o",
+		"follow.wrap":              "alt+$",
+		"follow.left":              "alt+left",
+		"follow.right":             "alt+right",
+		"browse.next":              "ctrl+n",
+		"browse.previous":          "ctrl+p",
+		"browse.reload":            "ctrl+l",
+		"browse.jump":              "alt+g",
+		"browse.time":              "alt+t",
+		"browse.index":             "alt+i",
+		"browse.wrap":              "alt+$",
+		"browse.left":              "alt+left",
+		"browse.right":             "alt+right",
+		"browse.copy":              "alt+w",
+		"browse.copy.raw":          "alt+w w",
+		"browse.copy.pretty":       "alt+w p",
+		"browse.copy.field":        "alt+w f",
+		"browse.copy.range":        "alt+w r",
+		"browse.copy.path":         "alt+w j",
+		"browse.diff":              "alt+d",
+		"browse.mark":              "alt+m",
+		"browse.bookmark.next":     "alt+}",
+		"browse.bookmark.previous": "alt+{",
+		"browse.trace":             "alt+c",
+		"browse.tree":              "alt+e",
+		"browse.tree.find":         "ctrl+s",
+		"browse.tree.pin":          "alt+p",
+		"query.filter":             "alt+f",
+		"query.save":               "alt+n",
+		"query.close":              "alt+k",
+		"trace.down":               "ctrl+n",
+		"trace.up":                 "ctrl+p",
+		"trace.close":              "alt+k",
+		"bookmarks.down":           "ctrl+n",
+		"bookmarks.up":             "ctrl+p",
+		"bookmarks.remove":         "ctrl+d",
+		"bookmarks.close":          "ctrl+g",
+	}),
+}
+
+// PresetNames returns the names of all
+// presets in alphabetical order
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (keymap Keymap) with(overrides Keymap) Keymap {
+	merged := make(Keymap, len(keymap)+len(overrides))
+	for name, keys := range keymap {
+		merged[name] = keys
+	}
+	for name, keys := range overrides {
+		merged[name] = keys
+	}
+	return merged
+}
+
+// active is the keymap used by Map.Handle
+var active = defaultKeymap
+
+// Use makes the keymap the active keymap. Must be called
+// before any component binds its actions.
+func Use(keymap Keymap) {
+	active = keymap
+}
+
+// Keys returns the key sequence of the action
+// in the active keymap
+func Keys(name string) []string {
+	return split(active[name])
+}
+
+// Resolve applies the overrides to the preset and checks the
+// result for unknown actions and conflicting keys. An empty
+// preset selects the default preset.
+func Resolve(preset string, overrides map[string]string) (Keymap, error) {
+
+	if preset == "" {
+		preset = "default"
+	}
+	base, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("keymap: %q does not exist (available: %s)", preset, strings.Join(PresetNames(), ", "))
+	}
+
+	var errs []string
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			errs = append(errs, fmt.Sprintf("keys.%s: unknown action", name))
+		}
+		if len(split(overrides[name])) == 0 {
+			errs = append(errs, fmt.Sprintf("keys.%s: must not be empty", name))
+		}
+	}
+
+	keymap := base.with(overrides)
+	errs = append(errs, conflicts(keymap)...)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n\t"))
+	}
+	return keymap, nil
+}
+
+// conflicts reports actions of overlapping scopes which are
+// bound to the same keys or where the keys of one action start
+// with the keys of another action which is not a Prefix.
+func conflicts(keymap Keymap) []string {
+
+	var errs []string
+	for i, a := range actions {
+		for _, b := range actions[i+1:] {
+			if !overlap(a.Scope(), b.Scope()) {
+				continue
+			}
+
+			keysA, keysB := split(keymap[a.Name]), split(keymap[b.Name])
+			short, long := a, b
+			if len(keysB) < len(keysA) {
+				short, long = b, a
+				keysA, keysB = keysB, keysA
+			}
+
+			if !hasPrefix(keysB, keysA) {
+				continue
+			}
+			if len(keysA) == len(keysB) {
+				errs = append(errs, fmt.Sprintf("keys: %s and %s are both bound to %q", a.Name, b.Name, keymap[a.Name]))
+				continue
+			}
+			if !short.Prefix {
+				errs = append(errs, fmt.Sprintf("keys: %s (%q) shadows %s (%q)", short.Name, keymap[short.Name], long.Name, keymap[long.Name]))
+			}
+		}
+	}
+	return errs
+}
+
+// stacked maps scopes to the scopes whose actions are active
+// at the same time: the query tab is built on top of the follow
+// view. Panels such as the bookmarks take all keys while open
+// and are not stacked on the tab below.
+var stacked = map[string][]string{
+	"query": {"follow"},
+}
+
+// overlap reports whether actions of both scopes
+// can be triggered by the same key at once
+func overlap(a string, b string) bool {
+	if a == b || a == globalScope || b == globalScope {
+		return true
+	}
+	for _, scope := range stacked[a] {
+		if scope == b {
+			return true
+		}
+	}
+	for _, scope := range stacked[b] {
+		if scope == a {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefix(keys []string, prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(keys) {
+		return false
+	}
+	for i := range prefix {
+		if keys[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// split breaks a key sequence into the keys
+// as reported by tea.KeyMsg.String
+func split(sequence string) []string {
+	keys := strings.Fields(sequence)
+	for i, k := range keys {
+		if k == leaderName {
+			keys[i] = " "
+		}
+	}
+	return keys
+}
+
+// Binding is an action together with the keys
+// triggering it in the active keymap
+type Binding struct {
+	Action
+	Keys string
+}
+
+// Reference lists all actions with their keys in the active
+// keymap. If query is not empty only actions whose name, keys,
+// description or help contain the query are listed.
+func Reference(query string) []Binding {
+
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var bindings []Binding
+	for _, action := range actions {
+		keys := Keys(action.Name)
+		for i, k := range keys {
+			keys[i] = Display(k)
+		}
+
+		binding := Binding{Action: action, Keys: strings.Join(keys, " ")}
+		if query != "" && !binding.matches(query) {
+			continue
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
+
+func (binding Binding) matches(query string) bool {
+	for _, field := range []string{binding.Name, binding.Keys, binding.Description, binding.Help} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Display returns a key as it is written in a key sequence
+func Display(k string) string {
+	if k == " " {
+		return leaderName
+	}
+	return k
+}
@@ -0,0 +1,146 @@
+package bindings
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+
+	for _, name := range PresetNames() {
+		keymap, err := Resolve(name, nil)
+		if err != nil {
+			t.Fatalf("[%s] unable to resolve preset: %v", name, err)
+		}
+
+		for _, action := range actions {
+			if len(split(keymap[action.Name])) == 0 {
+				t.Fatalf("[%s] action %s has no keys", name, action.Name)
+			}
+		}
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+
+	tt := []struct {
+		name      string
+		overrides map[string]string
+		want      string
+	}{
+		{
+			name:      "same keys in same scope",
+			overrides: map[string]string{"browse.next": "k"},
+			want:      "browse.next and browse.previous are both bound to",
+		},
+		{
+			name:      "global keys shadow component keys",
+			overrides: map[string]string{"global.quit": "p"},
+			want:      "global.quit and follow.pause are both bound to",
+		},
+		{
+			name:      "query tab keys clash with follow keys",
+			overrides: map[string]string{"query.filter": "p"},
+			want:      "follow.pause and query.filter are both bound to",
+		},
+		{
+			name:      "action shadows sequence",
+			overrides: map[string]string{"follow.latest": ":", "follow.jump": ": j"},
+			want:      `follow.latest (":") shadows follow.jump`,
+		},
+		{
+			name:      "unknown action",
+			overrides: map[string]string{"follow.fly": "f"},
+			want:      "keys.follow.fly: unknown action",
+		},
+	}
+
+	for _, tc := range tt {
+		_, err := Resolve("", tc.overrides)
+		if err == nil {
+			t.Fatalf("[%s] wanted error - got nil", tc.name)
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("[%s] wanted error to contain: %q - got: %q", tc.name, tc.want, err.Error())
+		}
+	}
+
+	// actions of different scopes may share keys
+	if _, err := Resolve("", map[string]string{"follow.pause": "j"}); err != nil {
+		t.Fatalf("wanted follow.pause and browse.next to share keys - got: %v", err)
+	}
+}
+
+func TestHandleUsesActiveKeymap(t *testing.T) {
+
+	keymap, err := Resolve("emacs", map[string]string{"global.switch.browse": "ctrl+x ctrl+b"})
+	if err != nil {
+		t.Fatalf("unable to resolve keymap: %v", err)
+	}
+	Use(keymap)
+	defer Use(defaultKeymap)
+
+	m := NewMap()
+
+	var called bool
+	m.Handle("global.switch.browse", func(msg tea.KeyMsg, count int) tea.Cmd {
+		called = true
+		return nil
+	})
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyCtrlX},
+		{Type: tea.KeyCtrlB},
+	} {
+		if !m.Matches(msg) {
+			t.Fatalf("Key: %q not found in Map", msg)
+		}
+		m.Exec(msg).Call(msg)
+	}
+
+	if !called {
+		t.Fatalf("wanted action bound to %q to be called", keymap["global.switch.browse"])
+	}
+}
+
+func TestReference(t *testing.T) {
+
+	keymap, err := Resolve("emacs", nil)
+	if err != nil {
+		t.Fatalf("unable to resolve keymap: %v", err)
+	}
+	Use(keymap)
+	defer Use(defaultKeymap)
+
+	all := Reference("")
+	if len(all) != len(actions) {
+		t.Fatalf("wanted all %d actions listed, got %d", len(actions), len(all))
+	}
+	for _, binding := range all {
+		if binding.Help == "" || binding.Description == "" {
+			t.Fatalf("action %s is not documented", binding.Name)
+		}
+	}
+
+	tt := []struct {
+		query string
+		want  []string
+	}{
+		{query: "CTRL+X B", want: []string{"global.switch.browse"}},
+		{query: "browse.re", want: []string{"browse.reload"}},
+		{query: "color theme", want: []string{"global.theme"}},
+		{query: "unknown", want: nil},
+	}
+
+	for _, tc := range tt {
+		var got []string
+		for _, binding := range Reference(tc.query) {
+			got = append(got, binding.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("[%s] wanted: %v, got: %v", tc.query, tc.want, got)
+		}
+	}
+}
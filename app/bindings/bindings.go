@@ -1,23 +1,58 @@
 package bindings
 
 import (
+	"sort"
+	"time"
+
 	"github.com/KonstantinGasser/scotty/debug"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kr/pretty"
 )
 
-type Func func(tea.KeyMsg) tea.Cmd
+// Func is the action bound to a key. count is the count
+// typed before the key (50j) and at least 1.
+type Func func(msg tea.KeyMsg, count int) tea.Cmd
 
 func (fn Func) Call(msg tea.KeyMsg) tea.Cmd {
+	return Bound{fn: fn, count: 1}.Call(msg)
+}
+
+var NilFunc Func = func(msg tea.KeyMsg, count int) tea.Cmd {
+	return nil
+}
+
+// Bound is a Func together with the count
+// it is called with
+type Bound struct {
+	fn    Func
+	count int
+	// expire is send along with the action if the
+	// action leaves a sequence pending which times out
+	expire tea.Cmd
+}
+
+func (b Bound) Call(msg tea.KeyMsg) tea.Cmd {
+	fn := b.fn
 	if fn == nil {
-		return NilFunc.Call(msg)
+		fn = NilFunc
+	}
+	count := b.count
+	if count < 1 {
+		count = 1
+	}
+
+	if b.expire == nil {
+		return fn(msg, count)
 	}
-	return fn(msg)
+	return tea.Batch(fn(msg, count), b.expire)
 }
 
-var NilFunc Func = func(msg tea.KeyMsg) tea.Cmd {
-	return nil
+// Expired is send once a pending sequence of
+// a Map with a timeout is not continued in time
+type Expired struct {
+	m   *Map
+	seq int
 }
 
 type Options map[string]*Node
@@ -27,6 +62,9 @@ type Node struct {
 	bindingLabel string
 	options      Options
 	action       Func
+	// description of the action shown
+	// while the sequence is pending
+	description string
 }
 
 func newNode(k string) *Node {
@@ -54,6 +92,13 @@ func (node *Node) Action(act Func) *Node {
 	return node
 }
 
+// Describe sets the description of the node's action
+// listed while the sequence is pending
+func (node *Node) Describe(description string) *Node {
+	node.description = description
+	return node
+}
+
 type SequenceTree struct {
 	onESC Func
 	root  *Node
@@ -87,10 +132,38 @@ type Binder interface {
 type seqOption struct {
 	onESC Func
 	node  *Node
+	// keys pressed so far
+	keys []string
 }
+
+const (
+	// maxCount limits the count prefix
+	maxCount  = 99999
+	repeatKey = "."
+)
+
 type Map struct {
 	next  *seqOption
 	binds map[string]*SequenceTree
+
+	// counts enables count prefixes and
+	// repeating the last action
+	counts bool
+	// count typed so far
+	count int
+	// counting and repeating flag the key passed to
+	// Matches to be handled by Exec as a count digit
+	// or a repetition
+	counting, repeating bool
+	// last is the last action completed
+	last *Bound
+
+	// timeout after which a pending sequence is aborted.
+	// Zero keeps sequences pending until completed or ESC
+	timeout time.Duration
+	// seq identifies the pending sequence an
+	// Expired message has been send for
+	seq int
 }
 
 func NewMap() *Map {
@@ -100,6 +173,28 @@ func NewMap() *Map {
 	}
 }
 
+// WithCounts enables count prefixes (50j) for the actions of
+// the map as well as repeating the last action with ".". Keys
+// bound in the map take precedence over "." and over digits not
+// continuing a count.
+func (m *Map) WithCounts() *Map {
+	m.counts = true
+	return m
+}
+
+// WithTimeout aborts pending sequences if the next key is not
+// pressed within the timeout. The owner of the map must pass
+// Expired messages to Expire.
+func (m *Map) WithTimeout(timeout time.Duration) *Map {
+	m.timeout = timeout
+	return m
+}
+
+// Count returns the count typed so far
+func (m *Map) Count() int {
+	return m.count
+}
+
 func (m *Map) Bind(k string) *SequenceTree {
 
 	if seq, ok := m.binds[k]; ok {
@@ -112,6 +207,37 @@ func (m *Map) Bind(k string) *SequenceTree {
 	return &seq
 }
 
+// Handle binds fn to the keys of the action in the active
+// Keymap. The returned Node allows to add further options
+// to the action's key sequence.
+func (m *Map) Handle(action string, fn Func) *Node {
+	keys := Keys(action)
+	if len(keys) == 0 {
+		// no keys, no way to trigger the action
+		return newNode("")
+	}
+
+	node := m.Bind(keys[0]).root
+	for _, k := range keys[1:] {
+		node = node.Option(k)
+	}
+
+	if act, ok := Lookup(action); ok {
+		node.Describe(act.Description)
+	}
+	return node.Action(fn)
+}
+
+// OnESC sets fn to be called if the key sequence
+// starting with the keys of the action is aborted
+func (m *Map) OnESC(action string, fn Func) {
+	keys := Keys(action)
+	if len(keys) == 0 {
+		return
+	}
+	m.Bind(keys[0]).OnESC(fn)
+}
+
 func (m *Map) Matches(msg tea.KeyMsg) bool {
 
 	try := msg.String()
@@ -135,13 +261,21 @@ func (m *Map) Matches(msg tea.KeyMsg) bool {
 		}
 		// user chose an option from the current next node
 		m.next.node = next
+		m.next.keys = append(m.next.keys, try)
+		return true
+	}
+
+	// once a count is started digits continue
+	// the count even if they are bound (10j)
+	if m.counts && m.count > 0 && isDigit(try) {
+		m.counting = true
 		return true
 	}
 
 	// try and see if the key exists in the bindings map
 	seq, ok := m.binds[try]
 	if !ok {
-		return false
+		return m.matchesCount(try)
 	}
 
 	// in case it does there might be further options
@@ -150,6 +284,7 @@ func (m *Map) Matches(msg tea.KeyMsg) bool {
 		m.next = &seqOption{
 			onESC: seq.onESC,
 			node:  seq.root,
+			keys:  []string{try},
 		}
 
 	}
@@ -157,7 +292,93 @@ func (m *Map) Matches(msg tea.KeyMsg) bool {
 	return true
 }
 
-func (m *Map) Exec(msg tea.KeyMsg) Func {
+// matchesCount reports whether the key is a digit of a
+// count prefix or the repeat key
+func (m *Map) matchesCount(try string) bool {
+	if !m.counts {
+		return false
+	}
+
+	if isDigit(try) && try != "0" {
+		m.counting = true
+		return true
+	}
+
+	if try == repeatKey && m.last != nil {
+		m.repeating = true
+		return true
+	}
+
+	return false
+}
+
+func isDigit(k string) bool {
+	return len(k) == 1 && k[0] >= '0' && k[0] <= '9'
+}
+
+// Exec returns the action of the key with the count typed
+// before. Exec must only be called if Matches returned true.
+func (m *Map) Exec(msg tea.KeyMsg) Bound {
+
+	if m.counting {
+		m.counting = false
+		if m.count*10+int(msg.String()[0]-'0') <= maxCount {
+			m.count = m.count*10 + int(msg.String()[0]-'0')
+		}
+		return Bound{fn: NilFunc}
+	}
+
+	if m.repeating {
+		m.repeating = false
+		repeat := *m.last
+		// a count typed before "." replaces
+		// the count of the last action
+		if m.count > 0 {
+			repeat.count = m.count
+			m.last = &repeat
+		}
+		m.count = 0
+		return repeat
+	}
+
+	esc := m.next != nil && msg.String() == "esc"
+
+	bound := Bound{fn: m.exec(msg), count: m.count}
+
+	if m.next != nil {
+		// sequence continues; keep the count for
+		// the action completing the sequence
+		if m.timeout > 0 {
+			m.seq++
+			expired := Expired{m: m, seq: m.seq}
+			bound.expire = tea.Tick(m.timeout, func(time.Time) tea.Msg { return expired })
+		}
+		return bound
+	}
+
+	m.count = 0
+	if !esc && bound.fn != nil {
+		last := bound
+		m.last = &last
+	}
+	return bound
+}
+
+// Expire aborts the pending sequence the message has been send
+// for and returns its OnESC action. Messages of sequences already
+// completed or aborted are ignored.
+func (m *Map) Expire(msg Expired) Bound {
+	if msg.m != m || msg.seq != m.seq || m.next == nil {
+		return Bound{fn: NilFunc}
+	}
+
+	onESC := m.next.onESC
+	m.next = nil
+	m.count = 0
+	return Bound{fn: onESC}
+}
+
+func (m *Map) exec(msg tea.KeyMsg) Func {
 
 	// we need to check if the KeyMsg matches the m.next.binding
 	// if so return m.next.action. if not we need to check if the
@@ -181,7 +402,7 @@ func (m *Map) Exec(msg tea.KeyMsg) Func {
 		// next check and update options
 		next, ok := m.next.node.options[msg.String()]
 		if !ok {
-			return NilFunc
+			return nil
 		}
 
 		if len(next.options) <= 0 {
@@ -195,12 +416,45 @@ func (m *Map) Exec(msg tea.KeyMsg) Func {
 
 	seq, ok := m.binds[msg.String()]
 	if !ok {
-		return NilFunc
+		return nil
 	}
 
 	return seq.root.action
 }
 
+// Hint is a key which can follow the
+// pending sequence and its description
+type Hint struct {
+	Key         string
+	Description string
+}
+
+// Pending returns the keys pressed so far and the keys which
+// can follow if a key sequence is pending. Keys leading to a
+// further sequence are described with a leading "+".
+func (m *Map) Pending() ([]string, []Hint, bool) {
+	if m.next == nil {
+		return nil, nil, false
+	}
+
+	keys := make([]string, len(m.next.keys))
+	for i, k := range m.next.keys {
+		keys[i] = Display(k)
+	}
+
+	var hints []Hint
+	for k, option := range m.next.node.options {
+		description := option.description
+		if len(option.options) > 0 {
+			description = "+" + description
+		}
+		hints = append(hints, Hint{Key: Display(k), Description: description})
+	}
+	sort.Slice(hints, func(i, j int) bool { return hints[i].Key < hints[j].Key })
+
+	return keys, hints, true
+}
+
 func (m *Map) Debug() {
 	debug.Print("AST of bindings.Map:\n%s\n", pretty.Sprint(*m))
 }
@@ -2,6 +2,7 @@ package bindings
 
 import (
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,7 +11,7 @@ func TestOptionSeqTree(t *testing.T) {
 
 	m := NewMap()
 
-	m.Bind(" ").Option("f").Action(func(km tea.KeyMsg) tea.Cmd {
+	m.Bind(" ").Option("f").Action(func(km tea.KeyMsg, count int) tea.Cmd {
 		return func() tea.Msg {
 			return "called: SPC"
 		}
@@ -44,9 +45,9 @@ func TestOnESCForSequence(t *testing.T) {
 
 	m := NewMap()
 
-	m.Bind(" ").OnESC(func(msg tea.KeyMsg) tea.Cmd {
+	m.Bind(" ").OnESC(func(msg tea.KeyMsg, count int) tea.Cmd {
 		return func() tea.Msg { return 1 }
-	}).Option("f").Action(func(km tea.KeyMsg) tea.Cmd { return nil })
+	}).Option("f").Action(func(km tea.KeyMsg, count int) tea.Cmd { return nil })
 
 	msgs := []tea.KeyMsg{
 		{
@@ -79,3 +80,127 @@ func TestOnESCForSequence(t *testing.T) {
 	}
 
 }
+
+func TestPendingListsOptions(t *testing.T) {
+
+	m := NewMap()
+
+	m.Handle("global.leader", NilFunc)
+	m.Handle("global.switch.follow", NilFunc)
+	m.Handle("global.recolor", NilFunc).Option("1").Describe("checkout-svc").Action(NilFunc)
+
+	if _, _, ok := m.Pending(); ok {
+		t.Fatalf("wanted no pending sequence before any key is pressed")
+	}
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	m.Matches(space)
+	m.Exec(space)
+
+	keys, hints, ok := m.Pending()
+	if !ok {
+		t.Fatalf("wanted pending sequence after %q", space)
+	}
+	if len(keys) != 1 || keys[0] != "SPC" {
+		t.Fatalf("wanted pressed keys: [SPC] - got: %v", keys)
+	}
+
+	want := []Hint{
+		{Key: "c", Description: "+recolor"},
+		{Key: "f", Description: "follow"},
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("wanted hints: %v - got: %v", want, hints)
+	}
+	for i := range want {
+		if hints[i] != want[i] {
+			t.Fatalf("wanted hint: %v - got: %v", want[i], hints[i])
+		}
+	}
+
+	c := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}
+	m.Matches(c)
+	m.Exec(c)
+
+	keys, hints, _ = m.Pending()
+	if len(keys) != 2 || len(hints) != 1 || hints[0].Description != "checkout-svc" {
+		t.Fatalf("wanted recolor options after SPC c - got: %v %v", keys, hints)
+	}
+}
+
+func keys(s string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+func TestCountAndRepeat(t *testing.T) {
+
+	m := NewMap().WithCounts()
+
+	var moved []int
+	m.Bind("j").Action(func(msg tea.KeyMsg, count int) tea.Cmd {
+		moved = append(moved, count)
+		return nil
+	})
+	// bound digits are not part of a count
+	m.Bind("0").Action(func(msg tea.KeyMsg, count int) tea.Cmd {
+		moved = append(moved, -1)
+		return nil
+	})
+
+	for _, msg := range keys("50jj.3.0") {
+		if !m.Matches(msg) {
+			t.Fatalf("Key: %q not found in Map", msg)
+		}
+		m.Exec(msg).Call(msg)
+	}
+
+	want := []int{50, 1, 1, 3, -1}
+	if len(moved) != len(want) {
+		t.Fatalf("wanted counts: %v - got: %v", want, moved)
+	}
+	for i := range want {
+		if moved[i] != want[i] {
+			t.Fatalf("wanted counts: %v - got: %v", want, moved)
+		}
+	}
+
+	if m := NewMap(); m.Matches(keys("5")[0]) {
+		t.Fatalf("wanted counts to be disabled by default")
+	}
+}
+
+func TestSequenceTimeout(t *testing.T) {
+
+	m := NewMap().WithTimeout(time.Millisecond)
+
+	var aborted bool
+	m.Bind(" ").OnESC(func(msg tea.KeyMsg, count int) tea.Cmd {
+		aborted = true
+		return nil
+	}).Option("f").Action(NilFunc)
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	m.Matches(space)
+	cmd := m.Exec(space).Call(space)
+	if cmd == nil {
+		t.Fatalf("wanted a timeout for the pending sequence")
+	}
+
+	// a message of an older sequence is ignored
+	m.Expire(Expired{m: m, seq: m.seq - 1}).Call(space)
+	if aborted {
+		t.Fatalf("wanted expired message of older sequence to be ignored")
+	}
+
+	m.Expire(Expired{m: m, seq: m.seq}).Call(space)
+	if !aborted {
+		t.Fatalf("wanted pending sequence to be aborted")
+	}
+	if _, _, ok := m.Pending(); ok {
+		t.Fatalf("wanted no pending sequence after timeout")
+	}
+}
@@ -0,0 +1,107 @@
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52"
+)
+
+// Mode decides how text is copied
+type Mode string
+
+const (
+	// Auto copies with OSC 52 unless the terminal is not
+	// expected to support it or the text is too large for
+	// it in which case the text is written to the file
+	Auto Mode = "auto"
+	// OSC52 always copies with the OSC 52 escape sequence
+	OSC52 Mode = "osc52"
+	// File always writes the text to the file
+	File Mode = "file"
+)
+
+// maxOSC52 is the largest text copied with OSC 52 in Auto mode.
+// Terminals (xterm, tmux) drop sequences of more than 100000
+// bytes which the base64 encoding of the text must fit in.
+const maxOSC52 = 74994
+
+var (
+	mode = Auto
+	// path of the file used as fallback
+	path = DefaultFile()
+	// out and environ are the terminal the escape sequence
+	// is written to. bubbletea's renderer writes each frame
+	// to os.Stdout with a single write from its own goroutine.
+	// Writes to the same file are serialized such that a
+	// sequence written at once ends up between two frames
+	// and never within one.
+	out     io.Writer = os.Stdout
+	environ           = os.Environ()
+)
+
+// DefaultFile returns the file the text is written
+// to if not configured otherwise
+func DefaultFile() string {
+	return filepath.Join(os.TempDir(), "scotty-clipboard.txt")
+}
+
+// ParseMode maps the name of a Mode as
+// used in the config to its Mode
+func ParseMode(name string) (Mode, error) {
+	switch Mode(name) {
+	case "", Auto:
+		return Auto, nil
+	case OSC52, File:
+		return Mode(name), nil
+	}
+	return Auto, fmt.Errorf("unknown clipboard mode %q (options: auto, osc52, file)", name)
+}
+
+// Configure sets the mode and the fallback file. An
+// empty file keeps the default file.
+func Configure(m Mode, file string) {
+	mode = m
+	if file != "" {
+		path = file
+	}
+}
+
+// Write copies the text and returns a note for the user
+// telling where the text has been copied to
+func Write(text string) (string, error) {
+
+	useOSC52 := mode == OSC52 || (mode == Auto && supported() && len(text) <= maxOSC52)
+	if useOSC52 {
+		// the sequence is wrapped for tmux and screen if
+		// scotty runs within either and written at once
+		var seq bytes.Buffer
+		osc52.NewOutput(&seq, environ).Copy(text)
+		if _, err := out.Write(seq.Bytes()); err != nil {
+			return "", fmt.Errorf("unable to copy: %w", err)
+		}
+		return "copied to clipboard", nil
+	}
+
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		return "", fmt.Errorf("unable to copy: %w", err)
+	}
+	return fmt.Sprintf("copied to %s", path), nil
+}
+
+// supported reports whether the terminal is
+// expected to understand OSC 52
+func supported() bool {
+	for _, env := range environ {
+		if !strings.HasPrefix(env, "TERM=") {
+			continue
+		}
+		term := strings.TrimPrefix(env, "TERM=")
+		return term != "" && term != "dumb" && term != "linux"
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+
+	defer func(m Mode, p string, e []string) {
+		mode, path, environ, out = m, p, e, os.Stdout
+	}(mode, path, environ)
+
+	file := filepath.Join(t.TempDir(), "clipboard.txt")
+
+	tt := []struct {
+		name    string
+		mode    Mode
+		environ []string
+		text    string
+		// wantSeq is the prefix of the escape sequence
+		// (empty if the text is written to the file)
+		wantSeq string
+	}{
+		{name: "osc52", mode: Auto, environ: []string{"TERM=xterm-256color"}, text: "hello", wantSeq: "\x1b]52;c;"},
+		{name: "kitty", mode: Auto, environ: []string{"TERM=xterm-kitty"}, text: "hello", wantSeq: "\x1b]52;c;!"},
+		{name: "tmux", mode: Auto, environ: []string{"TERM=screen", "TMUX=/tmp/tmux"}, text: "hello", wantSeq: "\x1bPtmux;"},
+		{name: "unsupported terminal", mode: Auto, environ: []string{"TERM=dumb"}, text: "hello"},
+		{name: "too large", mode: Auto, environ: []string{"TERM=xterm"}, text: strings.Repeat("x", maxOSC52+1)},
+		{name: "forced file", mode: File, environ: []string{"TERM=xterm"}, text: "hello"},
+		{name: "forced osc52", mode: OSC52, environ: []string{"TERM=dumb"}, text: "hello", wantSeq: "\x1b]52;c;"},
+	}
+
+	for _, tc := range tt {
+		os.Remove(file)
+
+		var buf writes
+		out, environ = &buf, tc.environ
+		Configure(tc.mode, file)
+
+		if _, err := Write(tc.text); err != nil {
+			t.Fatalf("[%s] unable to write: %v", tc.name, err)
+		}
+
+		if tc.wantSeq != "" {
+			if !strings.HasPrefix(buf.String(), tc.wantSeq) {
+				t.Fatalf("[%s] wanted sequence starting with %q, got: %q", tc.name, tc.wantSeq, buf.String())
+			}
+			if buf.n != 1 {
+				t.Fatalf("[%s] wanted the sequence written at once, got: %d writes", tc.name, buf.n)
+			}
+			if !strings.Contains(buf.String(), base64.StdEncoding.EncodeToString([]byte(tc.text))) {
+				t.Fatalf("[%s] wanted encoded text in sequence, got: %q", tc.name, buf.String())
+			}
+			continue
+		}
+
+		if buf.Len() > 0 {
+			t.Fatalf("[%s] wanted no escape sequence, got: %q", tc.name, buf.String())
+		}
+		data, err := os.ReadFile(file)
+		if err != nil || string(data) != tc.text {
+			t.Fatalf("[%s] wanted text in file, got: %q (err: %v)", tc.name, data, err)
+		}
+	}
+}
+
+// writes counts the writes to the buffer
+type writes struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *writes) Write(p []byte) (int, error) {
+	w.n++
+	return w.Buffer.Write(p)
+}
@@ -0,0 +1,246 @@
+package bookmarks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/info"
+	"github.com/KonstantinGasser/scotty/app/component/tailing"
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/store"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// panelWidthRatio is the share of the content
+	// width the panel takes
+	panelWidthRatio = 0.8
+	// panelChrome are the rows taken by the border,
+	// the title and the error line of the panel
+	panelChrome = 5
+	timeLayout  = "15:04:05"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// Model is a panel listing all bookmarks. It is shown
+// on top of the content while open.
+type Model struct {
+	open          bool
+	width, height int
+	bindings      *bindings.Map
+	store         *store.Store
+	list          []store.Bookmark
+	cursor        int
+	// top is the first bookmark shown
+	top int
+	err string
+}
+
+func New(lStore *store.Store) *Model {
+
+	model := &Model{
+		bindings: bindings.NewMap(),
+		store:    lStore,
+	}
+
+	model.bindings.Handle("bookmarks.down", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.move(1)
+		return nil
+	})
+
+	model.bindings.Handle("bookmarks.up", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.move(-1)
+		return nil
+	})
+
+	model.bindings.Handle("bookmarks.open", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if len(model.list) == 0 {
+			return nil
+		}
+		bookmark := model.list[model.cursor]
+		switch {
+		case bookmark.Restored:
+			model.err = fmt.Sprintf("log %d is from a previous session", bookmark.Offset)
+			return nil
+		case bookmark.Overwritten:
+			model.err = fmt.Sprintf("log %d has been overwritten by newer logs", bookmark.Offset)
+			return nil
+		}
+		model.Close()
+		return tailing.RequestBrowse(bookmark.Offset)
+	})
+
+	model.bindings.Handle("bookmarks.remove", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if len(model.list) == 0 {
+			return nil
+		}
+		bookmark := model.list[model.cursor]
+		model.store.RemoveBookmark(bookmark)
+		model.load()
+		return RequestRemove(bookmark.Offset)
+	})
+
+	model.bindings.Handle("bookmarks.close", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.Close()
+		return RequestClose()
+	})
+
+	return model
+}
+
+// Open shows the panel with the current bookmarks
+func (model *Model) Open() tea.Cmd {
+	model.open = true
+	model.cursor, model.top = 0, 0
+	model.load()
+	return info.RequestMode(info.ModeBookmarks)
+}
+
+// Close hides the panel
+func (model *Model) Close() {
+	model.open = false
+	model.err = ""
+}
+
+// IsOpen reports whether the panel is shown
+func (model *Model) IsOpen() bool {
+	return model.open
+}
+
+func (model *Model) load() {
+	model.list = model.store.Bookmarks()
+	if model.cursor >= len(model.list) {
+		model.cursor = len(model.list) - 1
+	}
+	if model.cursor < 0 {
+		model.cursor = 0
+	}
+}
+
+func (model *Model) move(delta int) {
+	model.cursor += delta
+	if model.cursor >= len(model.list) {
+		model.cursor = len(model.list) - 1
+	}
+	if model.cursor < 0 {
+		model.cursor = 0
+	}
+}
+
+func (model *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.Dimensions:
+		model.width = msg.Width()
+		model.height = msg.Height()
+
+	case tea.KeyMsg:
+		model.err = ""
+		if msg.Type == tea.KeyEsc {
+			model.Close()
+			return model, RequestClose()
+		}
+		if model.bindings.Matches(msg) {
+			return model, model.bindings.Exec(msg).Call(msg)
+		}
+	}
+	return model, nil
+}
+
+// View renders the panel. Bookmarks the buffer no
+// longer holds (including the ones of previous sessions)
+// are flagged and show the log as it was when bookmarked.
+func (model *Model) View() string {
+	theme := styles.Current()
+	width := int(float64(model.width) * panelWidthRatio)
+	// the padding takes a column on either side
+	inner := width - 2
+
+	rows := model.height - panelChrome
+	if rows < 1 {
+		rows = 1
+	}
+	if model.cursor < model.top {
+		model.top = model.cursor
+	}
+	if model.cursor >= model.top+rows {
+		model.top = model.cursor - rows + 1
+	}
+
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Bookmarks (%d)", len(model.list))),
+		"",
+	}
+	if len(model.list) == 0 {
+		lines = append(lines, faintStyle.Render("no bookmarks yet - bookmark a log with "+keys("follow.mark")+" in the follow view"))
+	}
+
+	overwritten := lipgloss.NewStyle().Foreground(theme.Error)
+	for i := model.top; i < len(model.list) && i < model.top+rows; i++ {
+		bookmark := model.list[i]
+
+		var line strings.Builder
+		fmt.Fprintf(&line, "◆ %-6d %s %s", bookmark.Offset, bookmark.Time.Format(timeLayout), bookmark.Label)
+		if bookmark.Note != "" {
+			fmt.Fprintf(&line, "  %s", bookmark.Note)
+		}
+		fmt.Fprintf(&line, "  %s", strings.ReplaceAll(bookmark.Raw, "\n", " "))
+		text := fit(line.String(), inner)
+
+		switch {
+		case i == model.cursor:
+			text = selectedStyle.Render(text)
+		case bookmark.Overwritten:
+			text = faintStyle.Render(text)
+		}
+		if bookmark.Overwritten {
+			flag := " overwritten"
+			if bookmark.Restored {
+				flag = " previous session"
+			}
+			text = fit(text, inner-len(flag)) + overwritten.Render(flag)
+		}
+		lines = append(lines, text)
+	}
+
+	if model.err != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(theme.Error).Render(model.err))
+	}
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Modal).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// fit truncates s to the width
+func fit(s string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	return truncate.StringWithTail(s, uint(width), "…")
+}
+
+func keys(action string) string {
+	var display []string
+	for _, k := range bindings.Keys(action) {
+		display = append(display, bindings.Display(k))
+	}
+	return strings.Join(display, " ")
+}
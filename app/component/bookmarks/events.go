@@ -0,0 +1,23 @@
+package bookmarks
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// CloseRequest is sent once the panel closes
+type CloseRequest struct{}
+
+func RequestClose() tea.Cmd {
+	return func() tea.Msg {
+		return CloseRequest{}
+	}
+}
+
+// RemoveRequest is sent once a bookmark is removed
+// from the panel. Views showing the bookmark need
+// to be rebuilt.
+type RemoveRequest uint32
+
+func RequestRemove(offset uint32) tea.Cmd {
+	return func() tea.Msg {
+		return RemoveRequest(offset)
+	}
+}
@@ -1,6 +1,7 @@
 package browsing
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/KonstantinGasser/scotty/app/bindings"
@@ -41,10 +42,13 @@ var (
 			AlignHorizontal(lipgloss.Center).
 			Render("working on it!\n\nBrowsing logs is not yet implemented")
 
-	defaultPromptTxt   = "type an index of a log to start browsing the logs. Use j/k to navigate up and down"
+	defaultPromptTxt   = "type an index, a time (hh:mm:ss) or a duration (30s) to start browsing the logs. Use j/k to navigate up and down"
 	defaultPromptChar  = "> "
-	focusedPromptChar  = "> index: "
+	focusedPromptChar  = "> jump to: "
 	defaultPromptStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	errStyle           = lipgloss.NewStyle()
+	countStyle         = lipgloss.NewStyle().Faint(true).PaddingLeft(1)
+	noticeStyle        = lipgloss.NewStyle().Faint(true)
 )
 
 type Model struct {
@@ -53,6 +57,16 @@ type Model struct {
 	bindings      *bindings.Map
 	prompt        textinput.Model
 	formatter     store.Formatter
+	// err of the last jump shown next
+	// to the prompt
+	err string
+	// notice of the last copy shown
+	// next to the prompt
+	notice string
+	// input is what the focused prompt asks for
+	input input
+	// traceFields are the fields a log is traced by
+	traceFields []string
 }
 
 func New(formatter store.Formatter) *Model {
@@ -60,64 +74,190 @@ func New(formatter store.Formatter) *Model {
 	prompt := textinput.New()
 	prompt.Placeholder = defaultPromptTxt
 	prompt.Prompt = defaultPromptChar
-	prompt.Validate = func(s string) error {
-		_, err := strconv.ParseInt(s, 10, 64)
-		return err
-	}
+	prompt.Validate = store.ValidTargetInput
 
 	model := &Model{
-		ready:     false,
-		width:     0,
-		height:    0,
-		bindings:  bindings.NewMap(),
-		prompt:    prompt,
-		formatter: formatter,
+		ready:       false,
+		width:       0,
+		height:      0,
+		bindings:    bindings.NewMap().WithCounts(),
+		prompt:      prompt,
+		formatter:   formatter,
+		traceFields: store.TraceFields,
 	}
 
-	model.bindings.Bind(":").
-		OnESC(
-			func(msg tea.KeyMsg) tea.Cmd {
-				model.prompt.Blur()
-				model.prompt.Reset()
-				return info.RequestMode(info.ModeBrowsing)
-			},
-		).
-		Action(
-			func(msg tea.KeyMsg) tea.Cmd {
-				if model.prompt.Focused() {
-					return nil
-				}
-				model.prompt.Reset()
-				model.prompt.Prompt = focusedPromptChar
-				return tea.Batch(model.prompt.Focus(), info.RequestMode(info.ModePromptActive))
-			},
-		).
-		Option("enter").Action(
-		func(msg tea.KeyMsg) tea.Cmd {
-			if !model.prompt.Focused() {
-				return nil
+	model.bindings.OnESC("browse.jump", model.closePrompt)
+	model.bindings.OnESC("browse.copy", model.closePrompt)
+
+	model.bindings.Handle("browse.jump", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputJump)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("browse.copy.raw", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.copy(model.formatter.Raw(count))
+	})
+
+	model.bindings.Handle("browse.copy.pretty", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.copy(model.formatter.Pretty())
+	})
+
+	model.bindings.Handle("browse.copy.field", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputField)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("browse.copy.range", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputRange)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("browse.copy.path", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.copy(model.formatter.TreePath())
+	})
+
+	model.bindings.Handle("browse.diff", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if model.formatter.Diffing() {
+			model.formatter.CloseDiff()
+			return nil
+		}
+		if _, ok := model.formatter.Marked(); ok {
+			if err := model.formatter.ShowDiff(); err != nil {
+				model.err = err.Error()
 			}
+			return nil
+		}
+		if err := model.formatter.MarkDiff(); err != nil {
+			model.err = err.Error()
+			return nil
+		}
+		model.notice = fmt.Sprintf("marked %d - select another log to diff with", model.formatter.CurrentIndex())
+		return nil
+	})
+
+	model.bindings.OnESC("browse.mark", model.closePrompt)
+
+	model.bindings.Handle("browse.mark", func(msg tea.KeyMsg, count int) tea.Cmd {
+		marked, err := model.formatter.ToggleBookmark()
+		if err != nil {
+			model.err = err.Error()
+			return nil
+		}
+		if !marked {
+			model.notice = fmt.Sprintf("removed bookmark %d", model.formatter.CurrentIndex())
+			return nil
+		}
+		return model.ask(inputNote)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("browse.bookmark.next", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.nextBookmark(1)
+	})
+
+	model.bindings.Handle("browse.bookmark.previous", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.nextBookmark(-1)
+	})
+
+	model.bindings.Handle("browse.trace", func(msg tea.KeyMsg, count int) tea.Cmd {
+		field, value, err := model.formatter.TraceValue(model.traceFields...)
+		if err != nil {
+			model.err = err.Error()
+			return nil
+		}
+		return RequestTrace(field, value)
+	})
+
+	model.bindings.OnESC("browse.tree.find", model.closePrompt)
+
+	model.bindings.Handle("browse.tree", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if err := model.formatter.Explore(); err != nil {
+			model.err = err.Error()
+		}
+		return info.RequestMode(model.Mode())
+	})
+
+	model.bindings.Handle("browse.tree.down", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeMove(count)
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.up", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeMove(-count)
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.pagedown", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeMove(count * model.height / 2)
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.pageup", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeMove(-count * model.height / 2)
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.toggle", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeToggle()
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.expand", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeExpand(true)
+		return nil
+	})
+
+	model.bindings.Handle("browse.tree.collapse", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreeExpand(false)
+		return nil
+	})
 
-			index, _ := strconv.Atoi(model.prompt.Value())
-			model.formatter.Load(index)
-			model.prompt.Blur()
+	model.bindings.Handle("browse.tree.find", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if !model.formatter.Exploring() {
+			return nil
+		}
+		return model.ask(inputFind)
+	}).Option("enter").Action(model.submit)
 
-			return info.RequestMode(info.ModeBrowsing)
-		})
+	model.bindings.Handle("browse.tree.pin", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.TreePin()
+		return nil
+	})
 
-	model.bindings.Bind("k").Action(func(msg tea.KeyMsg) tea.Cmd {
-		model.formatter.Privous()
+	model.bindings.Handle("browse.previous", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.Move(-count)
 		model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
 		return nil
 	})
 
-	model.bindings.Bind("j").Action(func(msg tea.KeyMsg) tea.Cmd {
-		model.formatter.Next()
+	model.bindings.Handle("browse.next", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.Move(count)
 		model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
 		return nil
 	})
 
-	model.bindings.Bind("r").Action(func(msg tea.KeyMsg) tea.Cmd {
+	model.bindings.Handle("browse.time", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.ToggleTime()
+		return nil
+	})
+
+	model.bindings.Handle("browse.index", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.ToggleIndex()
+		return nil
+	})
+
+	model.bindings.Handle("browse.wrap", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.ToggleWrap()
+		return nil
+	})
+
+	model.bindings.Handle("browse.left", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.ScrollHorizontal(-count)
+		return nil
+	})
+
+	model.bindings.Handle("browse.right", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.formatter.ScrollHorizontal(count)
+		return nil
+	})
+
+	model.bindings.Handle("browse.reload", func(msg tea.KeyMsg, count int) tea.Cmd {
 		model.formatter.Load(int(model.formatter.CurrentIndex()))
 		return nil
 	})
@@ -148,15 +288,46 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model.formatter.Resize(model.width, uint8(model.height))
 
 	case tea.KeyMsg:
+		model.err, model.notice = "", ""
 		if model.bindings.Matches(msg) {
 			cmds = append(cmds, model.bindings.Exec(msg).Call(msg))
 		}
 
+	// the wheel moves the selection while a click
+	// selects the clicked item (the prompt is on top)
+	case tea.MouseMsg:
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			model.formatter.Move(-1)
+		case tea.MouseWheelDown:
+			model.formatter.Move(1)
+		case tea.MouseLeft:
+			model.formatter.SelectAt(msg.X, msg.Y-promptHeight)
+		}
+		model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
+
+	case openView:
+		if !model.ready {
+			break
+		}
+		model.formatter.Load(int(msg))
+		model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
+		// the buffer overwrote the log in the meantime
+		if model.formatter.CurrentIndex() != uint32(msg) {
+			model.err = fmt.Sprintf("log %d is no longer buffered", msg)
+		}
+
 	case initView:
 		if !model.ready {
 			break
 		}
 		model.formatter.Load(0)
+
+	case reloadView:
+		if !model.ready {
+			break
+		}
+		model.formatter.Load(int(model.formatter.CurrentIndex()))
 	}
 
 	if model.ready {
@@ -167,11 +338,24 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return model, tea.Batch(cmds...)
 }
 
+// pendingCount returns the count typed so far (50 of 50j)
+func (model Model) pendingCount() string {
+	if model.bindings.Count() <= 0 {
+		return ""
+	}
+	return strconv.Itoa(model.bindings.Count())
+}
+
 func (model Model) View() string {
 
 	return lipgloss.JoinVertical(lipgloss.Left,
-		defaultPromptStyle.Render(
-			model.prompt.View(),
+		defaultPromptStyle.Copy().BorderForeground(styles.Current().Border).Render(
+			lipgloss.JoinHorizontal(lipgloss.Left,
+				model.prompt.View(),
+				errStyle.Copy().Foreground(styles.Current().Error).Render(model.err),
+				noticeStyle.Render(model.notice),
+				countStyle.Render(model.pendingCount()),
+			),
 		),
 		lipgloss.NewStyle().
 			Height(model.height).
@@ -180,3 +364,36 @@ func (model Model) View() string {
 			),
 	)
 }
+
+// WithTraceFields sets the fields logs are traced by
+func (model *Model) WithTraceFields(fields []string) *Model {
+	if len(fields) > 0 {
+		model.traceFields = fields
+	}
+	return model
+}
+
+// Mode returns the mode of the browse view
+// depending on whether the tree is open
+func (model Model) Mode() info.AppMode {
+	if model.formatter.Exploring() {
+		return info.ModeExploring
+	}
+	return info.ModeBrowsing
+}
+
+// Typing reports whether the prompt is focused
+func (model Model) Typing() bool {
+	return model.prompt.Focused()
+}
+
+// nextBookmark selects the next bookmarked
+// log (the previous if delta is negative)
+func (model *Model) nextBookmark(delta int) tea.Cmd {
+	if err := model.formatter.NextBookmark(delta); err != nil {
+		model.err = err.Error()
+		return nil
+	}
+	model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
+	return nil
+}
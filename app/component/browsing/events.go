@@ -7,3 +7,34 @@ type initView struct{}
 func RequestInitialView() tea.Msg {
 	return initView{}
 }
+
+type reloadView struct{}
+
+// RequestReload reloads the current page of the
+// formatter keeping the selected index
+func RequestReload() tea.Msg {
+	return reloadView{}
+}
+
+type openView uint32
+
+// RequestOpen selects the item at the
+// offset and loads the page starting at it
+func RequestOpen(offset uint32) tea.Cmd {
+	return func() tea.Msg {
+		return openView(offset)
+	}
+}
+
+// TraceRequest asks to open a tab following the
+// logs having the value in the field
+type TraceRequest struct {
+	Field string
+	Value string
+}
+
+func RequestTrace(field string, value string) tea.Cmd {
+	return func() tea.Msg {
+		return TraceRequest{Field: field, Value: value}
+	}
+}
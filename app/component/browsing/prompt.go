@@ -0,0 +1,138 @@
+package browsing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/app/clipboard"
+	"github.com/KonstantinGasser/scotty/app/component/info"
+	"github.com/KonstantinGasser/scotty/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// input is what the prompt asks for
+type input int
+
+const (
+	inputJump input = iota
+	inputField
+	inputRange
+	inputFind
+	inputNote
+)
+
+var prompts = map[input]struct {
+	char     string
+	validate func(string) error
+}{
+	inputJump:  {char: focusedPromptChar, validate: store.ValidTargetInput},
+	inputField: {char: "> copy field: "},
+	inputRange: {char: "> copy range: ", validate: validRangeInput},
+	inputFind:  {char: "> find key: "},
+	inputNote:  {char: "> note: "},
+}
+
+// ask focuses the prompt to ask for the input
+func (model *Model) ask(kind input) tea.Cmd {
+	if model.prompt.Focused() {
+		return nil
+	}
+
+	model.input = kind
+	model.prompt.Reset()
+	model.prompt.Prompt = prompts[kind].char
+	model.prompt.Validate = prompts[kind].validate
+	return tea.Batch(model.prompt.Focus(), info.RequestMode(info.ModePromptActive))
+}
+
+// submit handles the input of the prompt
+func (model *Model) submit(msg tea.KeyMsg, count int) tea.Cmd {
+	if !model.prompt.Focused() {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	switch model.input {
+	case inputJump:
+		target, err := store.ParseTarget(model.prompt.Value(), time.Now())
+		if err == nil {
+			err = model.formatter.LoadTarget(target)
+		}
+		if err != nil {
+			model.err = err.Error()
+		}
+	case inputField:
+		cmd = model.copy(model.formatter.Field(model.prompt.Value()))
+	case inputRange:
+		from, to, err := parseRange(model.prompt.Value())
+		if err != nil {
+			model.err = err.Error()
+			break
+		}
+		cmd = model.copy(model.formatter.Range(from, to))
+	case inputFind:
+		if err := model.formatter.TreeFind(model.prompt.Value()); err != nil {
+			model.err = err.Error()
+		}
+	case inputNote:
+		if err := model.formatter.Annotate(strings.TrimSpace(model.prompt.Value())); err != nil {
+			model.err = err.Error()
+			break
+		}
+		model.notice = fmt.Sprintf("bookmarked %d", model.formatter.CurrentIndex())
+	}
+
+	return tea.Batch(cmd, model.closePrompt(msg, count))
+}
+
+// closePrompt leaves the prompt showing the selected index
+func (model *Model) closePrompt(msg tea.KeyMsg, count int) tea.Cmd {
+	model.prompt.Blur()
+	model.prompt.Prompt = defaultPromptChar
+	model.prompt.Validate = store.ValidTargetInput
+	model.prompt.SetValue(strconv.Itoa(int(model.formatter.CurrentIndex())))
+	return info.RequestMode(model.Mode())
+}
+
+// copy copies the text to the clipboard
+// unless it could not be looked up
+func (model *Model) copy(text string, err error) tea.Cmd {
+	if err == nil {
+		model.notice, err = clipboard.Write(text)
+	}
+	if err != nil {
+		model.err = err.Error()
+	}
+	return nil
+}
+
+// parseRange parses a range of indexes (120-180)
+func parseRange(s string) (uint32, uint32, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q is not a range (use from-to)", s)
+	}
+
+	start, err := strconv.ParseUint(strings.TrimSpace(from), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not an index", from)
+	}
+	end, err := strconv.ParseUint(strings.TrimSpace(to), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not an index", to)
+	}
+	return uint32(start), uint32(end), nil
+}
+
+// validRangeInput reports whether s could become a
+// valid range while the user is still typing
+func validRangeInput(s string) error {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789- ", r) {
+			return fmt.Errorf("invalid character %q", r)
+		}
+	}
+	return nil
+}
@@ -4,27 +4,77 @@ import (
 	_ "embed"
 	"fmt"
 
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/info"
 	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
 )
 
 //go:embed DOCUMENTATION.md
 var readme string
 
+const (
+	promptHeight = 3
+)
+
+var (
+	defaultPromptTxt   = "search the key bindings"
+	defaultPromptChar  = "> "
+	focusedPromptChar  = "> search: "
+	defaultPromptStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+)
+
 type Model struct {
-	ready  bool
-	width  int
-	height int
-	view   viewport.Model
+	ready    bool
+	width    int
+	height   int
+	bindings *bindings.Map
+	prompt   textinput.Model
+	view     viewport.Model
+	// query the key bindings are
+	// currently filtered by
+	query string
 }
 
 func New() *Model {
 
-	return &Model{
-		ready: false,
+	prompt := textinput.New()
+	prompt.Placeholder = defaultPromptTxt
+	prompt.Prompt = defaultPromptChar
+
+	model := &Model{
+		ready:    false,
+		bindings: bindings.NewMap(),
+		prompt:   prompt,
 	}
+
+	model.bindings.OnESC("docs.search", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.prompt.Blur()
+		model.prompt.Reset()
+		model.prompt.Prompt = defaultPromptChar
+		model.render()
+		return info.RequestMode(info.ModeDocs)
+	})
+
+	model.bindings.Handle("docs.search", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if model.prompt.Focused() {
+			return nil
+		}
+		model.prompt.Prompt = focusedPromptChar
+		return tea.Batch(model.prompt.Focus(), info.RequestMode(info.ModePromptActive))
+	}).
+		// the search is applied while typing;
+		// enter only leaves the prompt
+		Option("enter").Action(func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.prompt.Blur()
+		return info.RequestMode(info.ModeDocs)
+	})
+
+	return model
 }
 
 func (model *Model) Init() tea.Cmd {
@@ -41,23 +91,38 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case styles.Dimensions:
 		model.width = msg.Width()
-		model.height = msg.Height()
+		model.height = msg.Height() - promptHeight
+		model.prompt.Width = model.width - len(focusedPromptChar) - 2
 
 		if !model.ready {
 			model.ready = true
+			model.view = viewport.New(model.width, model.height)
+		}
+		model.view.Width, model.view.Height = model.width, model.height
+		model.render()
+		return model, nil
+
+	// the docs are rendered with a glamour style
+	// matching the background of the theme
+	case styles.ThemeChanged:
+		model.render()
+		return model, nil
+
+	case tea.KeyMsg:
+		if model.bindings.Matches(msg) {
+			cmds = append(cmds, model.bindings.Exec(msg).Call(msg))
+			return model, tea.Batch(cmds...)
+		}
 
-			vp := viewport.New(model.width, model.height)
-			model.view = vp
+		if model.prompt.Focused() {
+			model.prompt, cmd = model.prompt.Update(msg)
+			cmds = append(cmds, cmd)
 
-			out, err := glamour.Render(readme, "dark")
-			if err != nil {
-				model.view.SetContent(fmt.Errorf("unable to render documents...\n[ERROR]: %s", err).Error())
-			} else {
-				model.view.SetContent(out)
+			if model.prompt.Value() != model.query {
+				model.render()
 			}
-
+			return model, tea.Batch(cmds...)
 		}
-
 	}
 
 	model.view, cmd = model.view.Update(msg)
@@ -66,6 +131,54 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return model, tea.Batch(cmds...)
 }
 
+// render renders the documentation together with
+// the key bindings matching the search
+func (model *Model) render() {
+	if !model.ready {
+		return
+	}
+	model.query = model.prompt.Value()
+
+	style := "dark"
+	if styles.Current().IsLight() {
+		style = "light"
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(model.width),
+	)
+	if err != nil {
+		model.view.SetContent(fmt.Errorf("unable to render documents...\n[ERROR]: %s", err).Error())
+		return
+	}
+
+	doc := readme
+	if model.query != "" {
+		// while searching only the key
+		// bindings are of interest
+		doc = ""
+	}
+
+	out, err := renderer.Render(doc + "\n" + keymap(model.query))
+	if err != nil {
+		model.view.SetContent(fmt.Errorf("unable to render documents...\n[ERROR]: %s", err).Error())
+		return
+	}
+	model.view.SetContent(out)
+	model.view.GotoTop()
+}
+
 func (model *Model) View() string {
-	return model.view.View()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		defaultPromptStyle.Copy().BorderForeground(styles.Current().Border).Render(
+			model.prompt.View(),
+		),
+		model.view.View(),
+	)
+}
+
+// Typing reports whether the prompt is focused
+func (model *Model) Typing() bool {
+	return model.prompt.Focused()
 }
@@ -0,0 +1,71 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/bindings"
+)
+
+// scopeTitles are the headings of the key binding
+// tables in the order they are listed
+var scopeTitles = []struct {
+	scope string
+	title string
+}{
+	{"global", "Global"},
+	{"follow", "Follow tab"},
+	{"browse", "Browse tab"},
+	{"query", "Query tab"},
+	{"trace", "Trace tab"},
+	{"docs", "Docs tab"},
+	{"bookmarks", "Bookmarks"},
+}
+
+// keymap renders the reference of all key bindings of the active
+// keymap as markdown. Only bindings matching the query are listed.
+func keymap(query string) string {
+
+	byScope := make(map[string][]bindings.Binding)
+	for _, binding := range bindings.Reference(query) {
+		byScope[binding.Scope()] = append(byScope[binding.Scope()], binding)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Key bindings\n\n")
+
+	if query != "" {
+		fmt.Fprintf(&b, "Bindings matching *%s*\n\n", escape(query))
+	}
+
+	var found bool
+	for _, s := range scopeTitles {
+		rows := byScope[s.scope]
+		if len(rows) == 0 {
+			continue
+		}
+		found = true
+
+		fmt.Fprintf(&b, "## %s\n\n", s.title)
+		b.WriteString("| Keys | Action | Description |\n")
+		b.WriteString("| ---- | ------ | ----------- |\n")
+		for _, row := range rows {
+			keys := "*unbound*"
+			if row.Keys != "" {
+				keys = "`" + row.Keys + "`"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", keys, escape(row.Name), escape(row.Help))
+		}
+		b.WriteString("\n")
+	}
+
+	if !found {
+		b.WriteString("No key binding matches the search.\n")
+	}
+	return b.String()
+}
+
+// escape prevents text from breaking the markdown table
+func escape(s string) string {
+	return strings.NewReplacer("|", "\\|", "*", "\\*", "_", "\\_").Replace(s)
+}
@@ -1,8 +1,11 @@
 package info
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/styles"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -25,6 +28,20 @@ func RequestSubscribe(label string, fg lipgloss.Color) tea.Cmd {
 	}
 }
 
+type requestRecolor struct {
+	label string
+	fg    lipgloss.Color
+}
+
+func RequestRecolor(label string, fg lipgloss.Color) tea.Cmd {
+	return func() tea.Msg {
+		return requestRecolor{
+			label: label,
+			fg:    fg,
+		}
+	}
+}
+
 type requestUnsubscribe string
 
 func RequestUnsubscribe(label string) tea.Cmd {
@@ -41,6 +58,53 @@ func RequestIncrement(label string) tea.Cmd {
 	}
 }
 
+type requestMemory struct {
+	used  uint64
+	limit uint64
+}
+
+// RequestMemory updates the memory used by the
+// buffered logs shown in the footer
+func RequestMemory(used uint64, limit uint64) tea.Cmd {
+	return func() tea.Msg {
+		return requestMemory{
+			used:  used,
+			limit: limit,
+		}
+	}
+}
+
+type requestHidden map[string]bool
+
+// RequestHidden marks the beams whose logs are
+// hidden from the follow view
+func RequestHidden(hidden map[string]bool) tea.Cmd {
+	return func() tea.Msg {
+		return requestHidden(hidden)
+	}
+}
+
+// MuteRequest asks to mute or unmute the beam
+// with the label in the follow view
+type MuteRequest string
+
+func RequestMute(label string) tea.Cmd {
+	return func() tea.Msg {
+		return MuteRequest(label)
+	}
+}
+
+// SoloRequest asks to only show the beam with
+// the label in the follow view (or all beams if
+// the beam is already shown solo)
+type SoloRequest string
+
+func RequestSolo(label string) tea.Cmd {
+	return func() tea.Msg {
+		return SoloRequest(label)
+	}
+}
+
 type requestPause struct{}
 
 func RequestPause() tea.Cmd {
@@ -59,30 +123,62 @@ func RequestResume() tea.Cmd {
 
 type AppMode struct {
 	Label string
-	Bg    lipgloss.Color
-	Opts  []string
+	// Bg picks the background of the mode
+	// from the active theme
+	Bg func(styles.Modes) lipgloss.Color
+	// Actions available in the mode. Shown with
+	// the keys of the active keymap as long as they
+	// fit the footer; the docs list all of them
+	Actions []string
+	Opts    []string
 }
 
+func followingBg(m styles.Modes) lipgloss.Color { return m.Following }
+func browsingBg(m styles.Modes) lipgloss.Color  { return m.Browsing }
+func pausedBg(m styles.Modes) lipgloss.Color    { return m.Paused }
+func commandBg(m styles.Modes) lipgloss.Color   { return m.Command }
+func inputBg(m styles.Modes) lipgloss.Color     { return m.Input }
+
 var (
-	ModeFollowing    AppMode = AppMode{Label: "FOLLOWING", Bg: lipgloss.Color("#98c379"), Opts: []string{" ·p pause/continue", " ·g go to latest"}}
-	ModeBrowsing     AppMode = AppMode{Label: "BROWSING", Bg: lipgloss.Color("#98c378"), Opts: []string{" ·j next", " ·k previous", " ·r reload"}}
-	ModePaused       AppMode = AppMode{Label: "PAUSED", Bg: lipgloss.Color("#ff9640")}
-	ModeGlobalCmd    AppMode = AppMode{Label: "GLOBAL", Bg: lipgloss.Color("54"), Opts: []string{" ·f follow", "·b browse", "·besc exit mode"}}
-	ModePromptActive AppMode = AppMode{Label: "INPUT (exit with ESC)", Bg: lipgloss.Color("54"), Opts: []string{"·besc exit input mode"}}
+	ModeFollowing    AppMode = AppMode{Label: "FOLLOWING", Bg: followingBg, Actions: []string{"follow.pause", "follow.latest", "follow.jump", "follow.mark"}}
+	ModeBrowsing     AppMode = AppMode{Label: "BROWSING", Bg: browsingBg, Actions: []string{"browse.next", "browse.previous", "browse.jump", "browse.tree", "browse.mark"}}
+	ModeExploring    AppMode = AppMode{Label: "EXPLORING", Bg: browsingBg, Actions: []string{"browse.tree.toggle", "browse.tree.find", "browse.tree.pin", "browse.tree"}}
+	ModeQuery        AppMode = AppMode{Label: "QUERY", Bg: followingBg, Actions: []string{"query.filter", "query.save", "query.close", "follow.pause"}}
+	ModeDocs         AppMode = AppMode{Label: "DOCS", Bg: browsingBg, Actions: []string{"docs.search"}}
+	ModeTrace        AppMode = AppMode{Label: "TRACE", Bg: followingBg, Actions: []string{"trace.open", "trace.close"}}
+	ModeBookmarks    AppMode = AppMode{Label: "BOOKMARKS", Bg: browsingBg, Actions: []string{"bookmarks.open", "bookmarks.remove", "bookmarks.close"}}
+	ModePaused       AppMode = AppMode{Label: "PAUSED", Bg: pausedBg, Actions: []string{"follow.pause", "follow.down", "follow.up", "follow.browse"}}
+	ModeGlobalCmd    AppMode = AppMode{Label: "GLOBAL", Bg: commandBg, Actions: []string{"global.switch.follow", "global.switch.browse", "global.switch.query", "global.switch.docs"}, Opts: []string{"·besc exit mode"}}
+	ModeRecolor      AppMode = AppMode{Label: "RECOLOR", Bg: commandBg}
+	ModePromptActive AppMode = AppMode{Label: "INPUT (exit with ESC)", Bg: inputBg, Opts: []string{"·besc exit input mode"}}
 )
 
 type requestMode struct {
 	mode string
-	bg   lipgloss.Color
+	bg   func(styles.Modes) lipgloss.Color
 	opts []string
 }
 
 func RequestMode(mode AppMode) tea.Cmd {
+
+	var opts []string
+	for _, name := range mode.Actions {
+		action, ok := bindings.Lookup(name)
+		keys := bindings.Keys(name)
+		if !ok || len(keys) == 0 {
+			continue
+		}
+		// within a sequence only the key
+		// completing the action is shown
+		opts = append(opts, fmt.Sprintf(" ·%s %s", bindings.Display(keys[len(keys)-1]), action.Description))
+	}
+	opts = append(opts, mode.Opts...)
+
 	return func() tea.Msg {
 		return requestMode{
 			mode: strings.ToUpper(mode.Label),
 			bg:   mode.Bg,
-			opts: mode.Opts,
+			opts: opts,
 		}
 	}
 }
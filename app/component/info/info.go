@@ -2,8 +2,11 @@ package info
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/KonstantinGasser/scotty/app/bindings"
 	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/config"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -30,13 +33,20 @@ type stat struct {
 	state     int
 	stateChar string
 	color     lipgloss.Color
-	compiled  string
+	// hidden is true while the logs of the
+	// beam are muted in the follow view
+	hidden   bool
+	compiled string
 }
 
 func (s *stat) increment() *stat { s.count++; return s }
 func (s *stat) compile() *stat {
 
-	s.compiled = s.style.Render(fmt.Sprintf("%s %d", s.stateChar, s.count))
+	style := s.style
+	if s.hidden {
+		style = style.Copy().Faint(true).Strikethrough(true)
+	}
+	s.compiled = style.Render(fmt.Sprintf("%s %d", s.stateChar, s.count))
 	return s
 }
 
@@ -54,6 +64,18 @@ type Model struct {
 	// O(1) search time
 	statsMap map[string]int
 	stats    []*stat
+	// memory is the compiled memory
+	// used by the buffered logs
+	memory string
+	// mode and opts are kept uncompiled in
+	// order to recompile them on a theme change
+	mode requestMode
+	opts []string
+	// more hints at the docs listing all keys
+	// if not all options fit the footer
+	more string
+	// width of the footer
+	width int
 }
 
 func New() *Model {
@@ -80,6 +102,7 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !model.ready {
 			model.ready = true
 		}
+		model.width = msg.Width()
 
 	case requestSubscribe:
 		index, ok := model.statsMap[msg.label]
@@ -92,7 +115,7 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		newStat := &stat{
 			label:     msg.label,
-			style:     lipgloss.NewStyle().Padding(0, 1).Foreground(msg.fg).Background(styles.BgFooter),
+			style:     lipgloss.NewStyle().Padding(0, 1).Foreground(msg.fg).Background(styles.Current().Footer),
 			state:     msg.state,
 			stateChar: symbolConnected,
 			count:     msg.count,
@@ -104,6 +127,15 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model.stats = append(model.stats, newStat)
 		model.statsMap[newStat.label] = len(model.stats) - 1
 
+	case requestRecolor:
+		index, ok := model.statsMap[msg.label]
+		if !ok {
+			break
+		}
+		model.stats[index].color = msg.fg
+		model.stats[index].style = model.stats[index].style.Copy().Foreground(msg.fg)
+		model.stats[index].compile()
+
 	case requestUnsubscribe:
 		index, ok := model.statsMap[string(msg)]
 		if !ok {
@@ -136,28 +168,135 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 		model.stats[index].increment().compile()
+	case requestHidden:
+		for _, st := range model.stats {
+			st.hidden = msg[st.label]
+			st.compile()
+		}
+
+	// a left click on a beam mutes it while a
+	// right click shows only the clicked beam
+	case tea.MouseMsg:
+		if msg.Type != tea.MouseLeft && msg.Type != tea.MouseRight {
+			break
+		}
+		label, ok := model.beamAt(msg.X)
+		if !ok {
+			break
+		}
+		if msg.Type == tea.MouseRight {
+			return model, RequestSolo(label)
+		}
+		return model, RequestMute(label)
+
+	case requestMemory:
+		usage := config.ByteSize(msg.used).String()
+		if msg.limit > 0 {
+			usage += "/" + config.ByteSize(msg.limit).String()
+		}
+		model.memory = lipgloss.NewStyle().
+			Padding(0, 1).
+			Faint(true).
+			Background(styles.Current().Footer).
+			Render(usage)
 	case requestMode:
 		// a message received here effects the baseInfo of the model (the mode).
 		// Options are stored seperatly and joined with the other information
 		// on the call of View.
-
-		model.baseInfo = lipgloss.NewStyle().
-			Padding(0, 1).
-			Bold(true).
-			Foreground(lipgloss.Color("#ffffff")).
-			Background(msg.bg).
-			Render(msg.mode)
-
+		model.mode = msg
 		if len(msg.opts) > 0 {
-			model.availOpts = []string{}
-			for _, opt := range msg.opts {
-				model.availOpts = append(model.availOpts, lipgloss.NewStyle().Bold(true).Background(styles.BgFooter).Render(opt))
-			}
+			model.opts = msg.opts
 		}
+		model.compileMode()
+
+	case styles.ThemeChanged:
+		for _, st := range model.stats {
+			st.style = st.style.Copy().Background(styles.Current().Footer)
+			st.compile()
+		}
+		model.compileMode()
 	}
 	return model, tea.Batch(cmds...)
 }
 
+func (model *Model) compileMode() {
+	theme := styles.Current()
+
+	var bg lipgloss.Color
+	if model.mode.bg != nil {
+		bg = model.mode.bg(theme.Modes)
+	}
+
+	model.baseInfo = lipgloss.NewStyle().
+		Padding(0, 1).
+		Bold(true).
+		Foreground(theme.Modes.Text).
+		Background(bg).
+		Render(model.mode.mode)
+
+	optStyle := lipgloss.NewStyle().Bold(true).Background(theme.Footer)
+	model.availOpts = []string{}
+	for _, opt := range model.opts {
+		model.availOpts = append(model.availOpts, optStyle.Render(opt))
+	}
+	model.more = optStyle.Render(moreHint())
+}
+
+// moreHint points to the docs tab which
+// lists the keys of all actions
+func moreHint() string {
+	var keys []string
+	for _, k := range bindings.Keys("global.switch.docs") {
+		keys = append(keys, bindings.Display(k))
+	}
+	if len(keys) == 0 {
+		return " ·…"
+	}
+	return fmt.Sprintf(" ·%s more", strings.Join(keys, " "))
+}
+
+// options returns the options of the mode which fit the width
+// in the order of the mode. If not all options fit, the ones
+// which do not are left out in favor of the hint at the docs
+// (no options are shown if not even the hint fits).
+func (model Model) options(width int) string {
+	var total int
+	for _, opt := range model.availOpts {
+		total += lipgloss.Width(opt)
+	}
+	if model.width <= 0 || total <= width {
+		return lipgloss.JoinHorizontal(lipgloss.Left, model.availOpts...)
+	}
+
+	width -= lipgloss.Width(model.more)
+	if width < 0 {
+		return ""
+	}
+	var shown []string
+	for _, opt := range model.availOpts {
+		if lipgloss.Width(opt) > width {
+			break
+		}
+		width -= lipgloss.Width(opt)
+		shown = append(shown, opt)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, shown...) + model.more
+}
+
+// beamAt returns the label of the
+// beam shown at the column x
+func (model Model) beamAt(x int) (string, bool) {
+	left := lipgloss.Width(model.baseInfo)
+	for _, st := range model.stats {
+		width := lipgloss.Width(st.compiled)
+		if x >= left && x < left+width {
+			return st.label, true
+		}
+		left += width
+	}
+	return "", false
+}
+
 func (model Model) View() string {
 
 	statsTmp := []string{}
@@ -165,9 +304,10 @@ func (model Model) View() string {
 		statsTmp = append(statsTmp, st.compiled)
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Left,
+	status := lipgloss.JoinHorizontal(lipgloss.Left,
 		model.baseInfo,
 		lipgloss.JoinHorizontal(lipgloss.Left, statsTmp...),
-		lipgloss.JoinHorizontal(lipgloss.Left, model.availOpts...),
+		model.memory,
 	)
+	return status + model.options(model.width-lipgloss.Width(status))
 }
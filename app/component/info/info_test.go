@@ -1,11 +1,41 @@
 package info
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/KonstantinGasser/scotty/app/styles"
 	"github.com/charmbracelet/lipgloss"
 )
 
+func TestFooterFitsWidth(t *testing.T) {
+
+	modes := []AppMode{ModeFollowing, ModeBrowsing, ModeExploring, ModeQuery, ModeDocs, ModeTrace, ModeBookmarks, ModePaused, ModeGlobalCmd, ModePromptActive}
+	for _, width := range []int{80, 50} {
+		for _, mode := range modes {
+			model := New()
+			model.Update(styles.NewDimensions(width, 2))
+			model.Update(RequestSubscribe("checkout-svc", lipgloss.Color("#ffffff")))
+			model.Update(RequestSubscribe("payment-svc", lipgloss.Color("#ffffff")))
+			model.Update(RequestMemory(12<<20, 256<<20)())
+			model.Update(RequestMode(mode)())
+
+			view := model.View()
+			if strings.Contains(view, "\n") || lipgloss.Width(view) > width {
+				t.Fatalf("[%s] wanted the footer in a row of %d columns, got %d columns: %q", mode.Label, width, lipgloss.Width(view), view)
+			}
+		}
+	}
+
+	// options which do not fit hint at the docs
+	model := New()
+	model.Update(styles.NewDimensions(40, 2))
+	model.Update(RequestMode(ModeBrowsing)())
+	if view := model.View(); !strings.Contains(view, "more") || strings.Contains(view, "tree") {
+		t.Fatalf("wanted the hint at the docs, got: %q", view)
+	}
+}
+
 /*
 Current benchmark results:
 
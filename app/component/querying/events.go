@@ -0,0 +1,23 @@
+package querying
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// SaveRequest asks to open a new tab
+// following the logs of the filter
+type SaveRequest struct {
+	Name   string
+	Filter string
+}
+
+func RequestSave(name string, filter string) tea.Cmd {
+	return func() tea.Msg {
+		return SaveRequest{Name: name, Filter: filter}
+	}
+}
+
+// CloseRequest asks to close the active query tab
+type CloseRequest struct{}
+
+func RequestClose() tea.Msg {
+	return CloseRequest{}
+}
@@ -1,33 +1,101 @@
 package querying
 
 import (
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/info"
+	"github.com/KonstantinGasser/scotty/app/component/tailing"
 	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/store"
+	"github.com/KonstantinGasser/scotty/stream"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	// filterHeight is the line showing the
+	// filter above the matching logs
+	filterHeight = 1
+)
+
 var (
-	notImplemeted = lipgloss.NewStyle().
-		Bold(true).
-		AlignVertical(lipgloss.Center).
-		AlignHorizontal(lipgloss.Center).
-		Render("working on it!\n\nQuerying logs is not yet implemented")
+	promptChar  = "> filter: "
+	filterStyle = lipgloss.NewStyle().Faint(true)
+	errStyle    = lipgloss.NewStyle()
 )
 
+// Model follows the logs matching a query. The logs are
+// rendered by a follow view such that pausing, jumping and
+// the table view work as in the follow tab.
 type Model struct {
 	ready         bool
 	width, height int
+	bindings      *bindings.Map
+	// prompt to type the filter
+	prompt textinput.Model
+	// follow renders the matching logs
+	follow *tailing.Model
+	name   string
+	query  store.Query
+	// unread counts the matching logs received
+	// while the tab has not been looked at
+	unread int
+	err    string
 }
 
-func New() *Model {
-	return &Model{
-		ready:  false,
-		width:  0,
-		height: 0,
+func New(name string, follow *tailing.Model, query store.Query) *Model {
+
+	prompt := textinput.New()
+	prompt.Prompt = promptChar
+	prompt.Placeholder = "level=error latency>200 msg~timeout"
+
+	model := &Model{
+		bindings: bindings.NewMap(),
+		prompt:   prompt,
+		follow:   follow.WithMode(info.ModeQuery),
+		name:     name,
+		query:    query,
 	}
+	model.follow.SetQuery(query)
+
+	model.bindings.OnESC("query.filter", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.closePrompt()
+	})
+
+	model.bindings.Handle("query.filter", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.prompt.SetValue(model.query.String())
+		model.prompt.CursorEnd()
+		return tea.Batch(model.prompt.Focus(), info.RequestMode(info.ModePromptActive))
+	}).Option("enter").Action(func(msg tea.KeyMsg, count int) tea.Cmd {
+		query, err := store.ParseQuery(model.prompt.Value())
+		if err != nil {
+			model.err = err.Error()
+			return model.closePrompt()
+		}
+		model.query = query
+		model.unread = 0
+		model.follow.SetQuery(query)
+		return model.closePrompt()
+	})
+
+	model.bindings.Handle("query.save", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if model.query.Empty() {
+			model.err = "type a filter to save first"
+			return nil
+		}
+		return RequestSave(model.query.String(), model.query.String())
+	})
+
+	model.bindings.Handle("query.close", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return RequestClose
+	})
+
+	return model
 }
 
-func (model Model) Init() tea.Cmd {
+func (model *Model) Init() tea.Cmd {
 	return nil
 }
 
@@ -35,26 +103,114 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	var (
 		cmds []tea.Cmd
+		cmd  tea.Cmd
 	)
 
 	switch msg := msg.(type) {
 	case styles.Dimensions:
-		if !model.ready {
-			model.ready = true
-		}
-
+		model.ready = true
 		model.width = msg.Width()
 		model.height = msg.Height()
+		model.prompt.Width = model.width - len(promptChar) - 1
+
+		_, cmd = model.follow.Update(styles.NewDimensions(model.width, model.height-filterHeight))
+		return model, cmd
+
+	case tea.KeyMsg:
+		model.err = ""
+		// keys typed into a prompt of the
+		// follow view belong to the follow view
+		if model.follow.Typing() {
+			break
+		}
+		if model.bindings.Matches(msg) {
+			return model, model.bindings.Exec(msg).Call(msg)
+		}
+		if model.prompt.Focused() {
+			model.prompt, cmd = model.prompt.Update(msg)
+			return model, cmd
+		}
+
+	// rows of the follow view start below the filter
+	case tea.MouseMsg:
+		msg.Y -= filterHeight
+		if msg.Y < 0 {
+			return model, nil
+		}
+		_, cmd = model.follow.Update(msg)
+		return model, cmd
+
+	// the follow view decides whether the log is
+	// shown such that the count matches its rows
+	case stream.Message:
+		before := model.follow.Shown()
+		_, cmd = model.follow.Update(msg)
+		if !model.query.Empty() {
+			model.unread += int(model.follow.Shown() - before)
+		}
+		return model, cmd
+	}
+
+	_, cmd = model.follow.Update(msg)
+	cmds = append(cmds, cmd)
+
+	if model.prompt.Focused() {
+		model.prompt, cmd = model.prompt.Update(msg)
+		cmds = append(cmds, cmd)
 	}
+
 	return model, tea.Batch(cmds...)
 }
 
-func (model Model) View() string {
-	return lipgloss.NewStyle().Render(
-		lipgloss.Place(
-			model.width, model.height,
-			lipgloss.Center, lipgloss.Center,
-			notImplemeted,
-		),
+func (model *Model) View() string {
+
+	filter := filterStyle.Render("filter: " + model.query.String())
+	switch {
+	case model.prompt.Focused():
+		filter = model.prompt.View()
+	case model.err != "":
+		filter = errStyle.Copy().Foreground(styles.Current().Error).Render(model.err)
+	case model.query.Empty():
+		filter = filterStyle.Render("all logs - type a filter with " + keys("query.filter"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().MaxWidth(model.width).Render(filter),
+		model.follow.View(),
 	)
 }
+
+// Title is the name of the query shown in the tab bar
+func (model *Model) Title() string {
+	return model.name
+}
+
+// Unread returns the number of matching logs received
+// since the tab has been looked at the last time
+func (model *Model) Unread() int {
+	return model.unread
+}
+
+// MarkRead resets the unread logs
+func (model *Model) MarkRead() {
+	model.unread = 0
+}
+
+// Typing reports whether a prompt is focused
+func (model *Model) Typing() bool {
+	return model.prompt.Focused() || model.follow.Typing()
+}
+
+func (model *Model) closePrompt() tea.Cmd {
+	model.prompt.Blur()
+	model.prompt.Reset()
+	return info.RequestMode(info.ModeQuery)
+}
+
+func keys(action string) string {
+	var display []string
+	for _, k := range bindings.Keys(action) {
+		display = append(display, bindings.Display(k))
+	}
+	return strings.Join(display, " ")
+}
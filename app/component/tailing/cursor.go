@@ -0,0 +1,111 @@
+package tailing
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cursor marks the log the browse view is opened
+// at with follow.browse
+type cursor struct {
+	active bool
+	// row of the first line of the log
+	row int
+}
+
+// moveCursor moves the cursor delta logs down (up if negative).
+// The first move shows the cursor on the latest log of the page
+// and pauses the view such that the logs stay in place.
+func (model *Model) moveCursor(delta int) tea.Cmd {
+	var cmd tea.Cmd
+	if model.state != paused {
+		model.state = paused
+		model.pager.PauseRender()
+		cmd = RequestPause()
+	}
+
+	if !model.cursor.active {
+		row, ok := model.lastLogRow()
+		if !ok {
+			model.err = "no log on the page"
+			return cmd
+		}
+		model.cursor = cursor{active: true, row: row}
+		return cmd
+	}
+
+	for ; delta > 0; delta-- {
+		row, ok := model.nextLogRow(model.cursor.row)
+		if !ok {
+			break
+		}
+		model.cursor.row = row
+	}
+	for ; delta < 0; delta++ {
+		if model.cursor.row <= 0 {
+			break
+		}
+		row, ok := model.logStart(model.cursor.row - 1)
+		if !ok {
+			break
+		}
+		model.cursor.row = row
+	}
+	return cmd
+}
+
+// logStart returns the first row of the log shown in the row
+func (model *Model) logStart(row int) (int, bool) {
+	offset, ok := model.pager.At(row)
+	if !ok {
+		return 0, false
+	}
+	for row > 0 {
+		if previous, ok := model.pager.At(row - 1); !ok || previous != offset {
+			break
+		}
+		row--
+	}
+	return row, true
+}
+
+// nextLogRow returns the first row of the log below the row
+func (model *Model) nextLogRow(row int) (int, bool) {
+	offset, ok := model.pager.At(row)
+	if !ok {
+		return 0, false
+	}
+	for next := row + 1; next < model.height; next++ {
+		if current, ok := model.pager.At(next); ok && current != offset {
+			return next, true
+		}
+	}
+	return 0, false
+}
+
+// lastLogRow returns the first row of the
+// latest log shown on the page
+func (model *Model) lastLogRow() (int, bool) {
+	for row := model.height - 1; row >= 0; row-- {
+		if _, ok := model.pager.At(row); ok {
+			return model.logStart(row)
+		}
+	}
+	return 0, false
+}
+
+// underCursor renders the rows of the log under the cursor reversed
+func (model *Model) underCursor(lines []string) []string {
+	if !model.cursor.active {
+		return lines
+	}
+	offset, ok := model.pager.At(model.cursor.row)
+	if !ok {
+		return lines
+	}
+	for row := range lines {
+		if current, ok := model.pager.At(row); ok && current == offset {
+			lines[row] = selectedStyle.Render(sgr.ReplaceAllString(lines[row], ""))
+		}
+	}
+	return lines
+}
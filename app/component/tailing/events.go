@@ -12,6 +12,16 @@ func RequestRefresh() tea.Cmd {
 	}
 }
 
+type forceRebuild struct{}
+
+// RequestRebuild causes the pager to re-read the current
+// page from the buffer. Required if stored items changed.
+func RequestRebuild() tea.Cmd {
+	return func() tea.Msg {
+		return forceRebuild{}
+	}
+}
+
 type PauseRequest struct{}
 
 func RequestPause() tea.Cmd {
@@ -27,3 +37,13 @@ func RequestResume() tea.Cmd {
 		return ResumeRequest{}
 	}
 }
+
+// BrowseRequest asks to open the item
+// at the offset in the browse view
+type BrowseRequest uint32
+
+func RequestBrowse(offset uint32) tea.Cmd {
+	return func() tea.Msg {
+		return BrowseRequest(offset)
+	}
+}
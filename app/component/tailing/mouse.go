@@ -0,0 +1,149 @@
+package tailing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// scrollStep is the number of items
+	// scrolled per turn of the mouse wheel
+	scrollStep = 3
+)
+
+var (
+	// sgr matches the escape sequences
+	// coloring the lines of the pager
+	sgr           = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	noticeStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+// selection is a range of rows selected
+// by dragging the mouse over the lines
+type selection struct {
+	active bool
+	// dragging is true while the mouse
+	// button has not been released
+	dragging bool
+	// paused is true if the view was
+	// paused by starting the selection
+	paused bool
+	anchor int
+	cursor int
+}
+
+func (s selection) contains(row int) bool {
+	if !s.active {
+		return false
+	}
+	from, to := s.anchor, s.cursor
+	if from > to {
+		from, to = to, from
+	}
+	return row >= from && row <= to
+}
+
+// mouse scrolls the page with the wheel, opens a clicked line
+// in the browse view and copies the lines selected by dragging
+func (model *Model) mouse(msg tea.MouseMsg) tea.Cmd {
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		return model.scroll(-scrollStep)
+	case tea.MouseWheelDown:
+		return model.scroll(scrollStep)
+
+	// while the button is held down motions
+	// are reported as left button events
+	case tea.MouseLeft:
+		model.selection.cursor = msg.Y
+		if model.selection.dragging {
+			return nil
+		}
+		model.selection = selection{active: true, dragging: true, anchor: msg.Y, cursor: msg.Y}
+
+		// the lines must not move while selecting
+		if model.state == paused {
+			return nil
+		}
+		model.state = paused
+		model.selection.paused = true
+		model.pager.PauseRender()
+		return RequestPause()
+
+	case tea.MouseRelease:
+		if !model.selection.dragging {
+			return nil
+		}
+		model.selection.dragging = false
+
+		// a click opens the line in the browse view
+		// and does not keep the view paused
+		if model.selection.anchor == model.selection.cursor {
+			var cmds []tea.Cmd
+			offset, ok := model.pager.At(msg.Y)
+			if ok {
+				cmds = append(cmds, RequestBrowse(offset))
+			}
+
+			if model.selection.paused {
+				model.state = running
+				model.cursor = cursor{}
+				model.pager.ResumeRender()
+				model.pager.Refresh()
+				cmds = append(cmds, RequestResume())
+			}
+			model.selection = selection{}
+			return tea.Batch(cmds...)
+		}
+
+		logs := model.pager.Selection(model.selection.anchor, model.selection.cursor)
+		if len(logs) == 0 {
+			model.selection = selection{}
+			return nil
+		}
+		note, err := clipboard.Write(strings.Join(logs, "\n"))
+		if err != nil {
+			model.err = err.Error()
+			return nil
+		}
+		model.notice = fmt.Sprintf("%d log(s) %s", len(logs), note)
+		return nil
+	}
+	return nil
+}
+
+// scroll moves the page by delta items and pauses
+// the view if it is still following the latest logs
+func (model *Model) scroll(delta int) tea.Cmd {
+	model.selection = selection{}
+	if err := model.pager.Scroll(delta); err != nil {
+		model.err = err.Error()
+		return nil
+	}
+
+	if model.state == paused {
+		return nil
+	}
+	model.state = paused
+	return RequestPause()
+}
+
+// highlight renders the selected rows of the page reversed
+func (model *Model) highlight(lines []string) []string {
+	if !model.selection.active {
+		return lines
+	}
+	for row := range lines {
+		if model.selection.contains(row) {
+			lines[row] = selectedStyle.Render(sgr.ReplaceAllString(lines[row], ""))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,115 @@
+package tailing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/app/component/info"
+	"github.com/KonstantinGasser/scotty/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// input is what the prompt asks for
+type input int
+
+const (
+	inputJump input = iota
+	inputColumns
+	inputSort
+	inputNote
+)
+
+var prompts = map[input]struct {
+	char        string
+	placeholder string
+	validate    func(string) error
+}{
+	inputJump:    {char: promptChar, placeholder: "index, hh:mm:ss or 30s", validate: store.ValidTargetInput},
+	inputColumns: {char: "> columns: ", placeholder: "level, msg, latency (empty for lines)"},
+	inputSort:    {char: "> sort by: ", placeholder: "latency (-latency descending, empty to unsort)"},
+	inputNote:    {char: "> note: ", placeholder: "optional note (enter to skip)"},
+}
+
+// ask focuses the prompt to ask for the input
+func (model *Model) ask(kind input) tea.Cmd {
+	if model.prompt.Focused() {
+		return nil
+	}
+
+	model.input = kind
+	model.prompt.Reset()
+	model.prompt.Prompt = prompts[kind].char
+	model.prompt.Placeholder = prompts[kind].placeholder
+	model.prompt.Validate = prompts[kind].validate
+	if kind == inputColumns {
+		model.prompt.SetValue(strings.Join(model.columns, ", "))
+		model.prompt.CursorEnd()
+	}
+	return tea.Batch(model.prompt.Focus(), info.RequestMode(info.ModePromptActive))
+}
+
+// submit handles the input of the prompt
+func (model *Model) submit(msg tea.KeyMsg, count int) tea.Cmd {
+	if !model.prompt.Focused() {
+		return nil
+	}
+	value := strings.TrimSpace(model.prompt.Value())
+
+	var err error
+	switch model.input {
+	case inputJump:
+		var target store.Target
+		target, err = store.ParseTarget(value, time.Now())
+		if err == nil {
+			err = model.pager.Jump(target)
+		}
+		if err == nil {
+			model.prompt.Blur()
+			model.prompt.Reset()
+			model.state = paused
+			return RequestPause()
+		}
+
+	case inputColumns:
+		model.columns = nil
+		for _, column := range strings.Split(value, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				model.columns = append(model.columns, column)
+			}
+		}
+		model.pager.SetColumns(model.columns...)
+
+	case inputSort:
+		column := strings.TrimPrefix(value, "-")
+		err = model.pager.SortBy(column, strings.HasPrefix(value, "-"))
+		if err == nil && column != "" && model.state != paused {
+			model.prompt.Blur()
+			model.prompt.Reset()
+			model.state = paused
+			return RequestPause()
+		}
+
+	case inputNote:
+		err = model.pager.Annotate(model.marking, value)
+		if err == nil {
+			model.notice = fmt.Sprintf("bookmarked %d", model.marking)
+		}
+	}
+
+	if err != nil {
+		model.err = err.Error()
+	}
+	return model.closePrompt(msg, count)
+}
+
+// closePrompt leaves the prompt and returns to the
+// mode the view has been in before
+func (model *Model) closePrompt(msg tea.KeyMsg, count int) tea.Cmd {
+	model.prompt.Blur()
+	model.prompt.Reset()
+	if model.state == paused {
+		return info.RequestMode(info.ModePaused)
+	}
+	return info.RequestMode(model.mode)
+}
@@ -1,11 +1,17 @@
 package tailing
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/info"
 	"github.com/KonstantinGasser/scotty/app/styles"
 	"github.com/KonstantinGasser/scotty/store"
 	"github.com/KonstantinGasser/scotty/stream"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 const (
@@ -16,25 +22,60 @@ const (
 	paused
 )
 
+var (
+	promptChar = "> jump to: "
+	errStyle   = lipgloss.NewStyle()
+)
+
 type Model struct {
 	ready         bool
 	width, height int
 	pager         store.Pager
 	state         int
 	bindings      *bindings.Map
+	// prompt to jump to an index or point
+	// in time (see store.ParseTarget)
+	prompt textinput.Model
+	err    string
+	// notice is shown in place of the last line
+	// until the next key is pressed
+	notice string
+	// selection of rows dragged with the mouse
+	selection selection
+	// cursor marks the log to browse
+	cursor cursor
+	// input is what the focused prompt asks for
+	input input
+	// columns of the table view
+	columns []string
+	// mode is requested once a prompt is
+	// closed while the view is not paused
+	mode info.AppMode
+	// marking is the offset of the log
+	// the note prompt is asking for
+	marking uint32
 }
 
 func New(pager store.Pager) *Model {
+
+	prompt := textinput.New()
+	prompt.Prompt = promptChar
+	prompt.Placeholder = "index, hh:mm:ss or 30s"
+	prompt.Validate = store.ValidTargetInput
+
 	model := &Model{
 		ready:    false,
 		pager:    pager,
 		state:    unset,
-		bindings: bindings.NewMap(),
+		bindings: bindings.NewMap().WithCounts(),
+		prompt:   prompt,
+		mode:     info.ModeFollowing,
 	}
 
-	model.bindings.Bind("p").Action(func(msg tea.KeyMsg) tea.Cmd {
+	model.bindings.Handle("follow.pause", func(msg tea.KeyMsg, count int) tea.Cmd {
 		if model.state == paused {
 			model.state = running
+			model.cursor = cursor{}
 			model.pager.ResumeRender()
 			model.pager.Refresh()
 			return RequestResume()
@@ -45,11 +86,109 @@ func New(pager store.Pager) *Model {
 		return RequestPause()
 	})
 
-	model.bindings.Bind("g").Action(func(msg tea.KeyMsg) tea.Cmd {
+	model.bindings.Handle("follow.latest", func(msg tea.KeyMsg, count int) tea.Cmd {
 		model.pager.Refresh()
 		return nil
 	})
 
+	model.bindings.Handle("follow.time", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.pager.ToggleTime()
+		return nil
+	})
+
+	model.bindings.Handle("follow.index", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.pager.ToggleIndex()
+		return nil
+	})
+
+	model.bindings.Handle("follow.wrap", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.pager.ToggleWrap()
+		return nil
+	})
+
+	model.bindings.Handle("follow.left", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if err := model.pager.ScrollHorizontal(-count); err != nil {
+			model.err = err.Error()
+		}
+		return nil
+	})
+
+	model.bindings.Handle("follow.right", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if err := model.pager.ScrollHorizontal(count); err != nil {
+			model.err = err.Error()
+		}
+		return nil
+	})
+
+	model.bindings.Handle("follow.down", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.moveCursor(count)
+	})
+
+	model.bindings.Handle("follow.up", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.moveCursor(-count)
+	})
+
+	model.bindings.Handle("follow.browse", func(msg tea.KeyMsg, count int) tea.Cmd {
+		offset, ok := model.pager.At(model.cursor.row)
+		if !model.cursor.active {
+			offset, ok = model.latestShown(1)
+		}
+		if !ok {
+			model.err = "no log to browse on the page"
+			return nil
+		}
+		return RequestBrowse(offset)
+	})
+
+	model.bindings.OnESC("follow.jump", model.closePrompt)
+	model.bindings.OnESC("follow.columns", model.closePrompt)
+	model.bindings.OnESC("follow.sort", model.closePrompt)
+	model.bindings.OnESC("follow.mark", model.closePrompt)
+
+	model.bindings.Handle("follow.jump", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputJump)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("follow.table", func(msg tea.KeyMsg, count int) tea.Cmd {
+		if model.pager.Columns() != nil {
+			model.pager.SetColumns()
+			return nil
+		}
+		if len(model.columns) == 0 {
+			model.err = "no columns picked yet (" + strings.Join(bindings.Keys("follow.columns"), " ") + ")"
+			return nil
+		}
+		model.pager.SetColumns(model.columns...)
+		return nil
+	})
+
+	model.bindings.Handle("follow.columns", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputColumns)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("follow.sort", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return model.ask(inputSort)
+	}).Option("enter").Action(model.submit)
+
+	model.bindings.Handle("follow.mark", func(msg tea.KeyMsg, count int) tea.Cmd {
+		offset, ok := model.latestShown(count)
+		if !ok {
+			model.err = "no log to bookmark on the page"
+			return nil
+		}
+		marked, err := model.pager.ToggleBookmark(offset)
+		if err != nil {
+			model.err = err.Error()
+			return nil
+		}
+		if !marked {
+			model.notice = fmt.Sprintf("removed bookmark %d", offset)
+			return nil
+		}
+		model.marking = offset
+		return model.ask(inputNote)
+	}).Option("enter").Action(model.submit)
+
 	return model
 }
 
@@ -60,6 +199,7 @@ func (model *Model) Init() tea.Cmd {
 func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
 		cmds []tea.Cmd
+		cmd  tea.Cmd
 	)
 
 	switch msg := msg.(type) {
@@ -74,24 +214,109 @@ func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		model.pager.Resize(model.width, model.height)
 
+	case tea.MouseMsg:
+		model.err, model.notice = "", ""
+		cmds = append(cmds, model.mouse(msg))
+
 	case tea.KeyMsg:
-		if !model.bindings.Matches(msg) {
-			return model, tea.Batch(cmds...)
+		model.err, model.notice = "", ""
+		model.selection = selection{}
+		if model.bindings.Matches(msg) {
+			cmds = append(cmds, model.bindings.Exec(msg).Call(msg))
+			break
+		}
+
+		if model.prompt.Focused() {
+			model.prompt, cmd = model.prompt.Update(msg)
+			cmds = append(cmds, cmd)
 		}
 
-		cmds = append(cmds, model.bindings.Exec(msg).Call(msg))
 	case stream.Message:
 		model.pager.MovePosition()
+	case forceRefresh:
+		model.pager.Refresh()
+	case forceRebuild:
+		model.pager.Rebuild()
+		model.pager.Refresh()
 	}
 
 	return model, tea.Batch(cmds...)
 }
 
 func (model *Model) View() string {
-	return model.pager.String()
+	if !model.prompt.Focused() && model.err == "" && model.notice == "" && !model.selection.active && !model.cursor.active {
+		return model.pager.String()
+	}
+
+	lines := model.highlight(model.underCursor(strings.Split(model.pager.String(), "\n")))
+	if !model.prompt.Focused() && model.err == "" && model.notice == "" {
+		return strings.Join(lines, "\n")
+	}
+
+	// the prompt replaces the last line of the page
+	if len(lines) > 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	if model.err != "" {
+		return strings.Join(append(lines, errStyle.Copy().Foreground(styles.Current().Error).Render(model.err)), "\n")
+	}
+	if model.notice != "" {
+		return strings.Join(append(lines, noticeStyle.Render(model.notice)), "\n")
+	}
+	return strings.Join(append(lines, model.prompt.View()), "\n")
+}
+
+// WithColumns sets the columns of the table view
+// until the user picks other columns
+func (model *Model) WithColumns(columns []string) *Model {
+	model.columns = columns
+	return model
+}
+
+// WithMode sets the mode of the view
+// while following the logs
+func (model *Model) WithMode(mode info.AppMode) *Model {
+	model.mode = mode
+	return model
+}
+
+// SetQuery shows only the logs matching the query
+func (model *Model) SetQuery(query store.Query) {
+	model.pager.SetQuery(query)
 }
 
 func (model *Model) setDimensions(width, height int) {
 	model.width = width
 	model.height = height
 }
+
+// Shown returns the number of logs added to the
+// view as they were received (see store.Pager.Shown)
+func (model *Model) Shown() uint64 {
+	return model.pager.Shown()
+}
+
+// Typing reports whether the prompt is focused
+func (model *Model) Typing() bool {
+	return model.prompt.Focused()
+}
+
+// latestShown returns the offset of the count-th latest
+// log shown on the page. Logs broken into multiple
+// lines are counted once.
+func (model *Model) latestShown(count int) (uint32, bool) {
+	var seen int
+	var previous *uint32
+	for row := model.height; row >= 0; row-- {
+		offset, ok := model.pager.At(row)
+		if !ok || (previous != nil && *previous == offset) {
+			continue
+		}
+		if seen++; seen == count {
+			return offset, true
+		}
+		previous = &offset
+	}
+	return 0, false
+}
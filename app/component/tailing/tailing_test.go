@@ -25,12 +25,12 @@ func BenchmarkUpdateSingelBeamNoView(b *testing.B) {
 	model := New(reader)
 
 	for i := 0; i < 2048; i++ {
-		buffer.Insert("hello-world", 14, []byte(`hello-world | {"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
+		buffer.Insert("hello-world", time.Now(), []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
 	}
 
 	msg := stream.Message{
 		Label: "hello-world",
-		Data:  []byte(`hello-world | {"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`),
+		Data:  []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`),
 	}
 
 	for i := 0; i < b.N; i++ {
@@ -54,12 +54,12 @@ func BenchmarkUpdateSingelBeamWithView(b *testing.B) {
 	model := New(reader)
 
 	for i := 0; i < 2048; i++ {
-		buffer.Insert("hello-world", 14, []byte(`hello-world | {"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
+		buffer.Insert("hello-world", time.Now(), []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
 	}
 
 	msg := stream.Message{
 		Label: "hello-world",
-		Data:  []byte(`hello-world | {"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`),
+		Data:  []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`),
 	}
 
 	for i := 0; i < b.N; i++ {
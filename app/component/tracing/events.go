@@ -0,0 +1,10 @@
+package tracing
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// CloseRequest asks to close the active trace tab
+type CloseRequest struct{}
+
+func RequestClose() tea.Msg {
+	return CloseRequest{}
+}
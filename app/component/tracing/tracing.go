@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/component/tailing"
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/store"
+	"github.com/KonstantinGasser/scotty/stream"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var errStyle = lipgloss.NewStyle()
+
+// Model follows one request across beams: every log having
+// the traced value in its field in the order it arrived
+type Model struct {
+	width, height int
+	bindings      *bindings.Map
+	trace         *store.Trace
+	// unread counts the hops received while
+	// the tab has not been looked at
+	unread int
+	err    string
+}
+
+func New(trace *store.Trace) *Model {
+
+	model := &Model{
+		bindings: bindings.NewMap().WithCounts(),
+		trace:    trace,
+	}
+
+	model.bindings.Handle("trace.down", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.trace.Move(count)
+		return nil
+	})
+
+	model.bindings.Handle("trace.up", func(msg tea.KeyMsg, count int) tea.Cmd {
+		model.trace.Move(-count)
+		return nil
+	})
+
+	model.bindings.Handle("trace.open", func(msg tea.KeyMsg, count int) tea.Cmd {
+		hop, ok := model.trace.Selected()
+		if !ok {
+			return nil
+		}
+		if model.trace.Overwritten(hop) {
+			model.err = fmt.Sprintf("log %d has been overwritten by newer logs", hop.Offset)
+			return nil
+		}
+		return tailing.RequestBrowse(hop.Offset)
+	})
+
+	model.bindings.Handle("trace.close", func(msg tea.KeyMsg, count int) tea.Cmd {
+		return RequestClose
+	})
+
+	return model
+}
+
+func (model *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (model *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.Dimensions:
+		model.width = msg.Width()
+		model.height = msg.Height()
+
+	case tea.KeyMsg:
+		model.err = ""
+		if model.bindings.Matches(msg) {
+			return model, model.bindings.Exec(msg).Call(msg)
+		}
+
+	// the store holds the message already
+	case stream.Message:
+		before := model.trace.Len()
+		if model.trace.Update() {
+			model.unread += model.trace.Len() - before
+		}
+	}
+	return model, nil
+}
+
+func (model *Model) View() string {
+	height := model.height
+	if model.err != "" {
+		height--
+	}
+
+	view := model.trace.Render(model.width, height)
+	if model.err != "" {
+		view += "\n" + errStyle.Copy().Foreground(styles.Current().Error).Render(model.err)
+	}
+	return lipgloss.NewStyle().Height(model.height).MaxHeight(model.height).Render(view)
+}
+
+// Title is the traced value shown in the tab bar
+func (model *Model) Title() string {
+	return "trace: " + model.trace.Value
+}
+
+// Traces reports whether the tab traces the value in the field
+func (model *Model) Traces(field string, value string) bool {
+	return model.trace.Field == field && model.trace.Value == value
+}
+
+// Unread returns the number of hops received
+// since the tab has been looked at the last time
+func (model *Model) Unread() int {
+	return model.unread
+}
+
+// MarkRead resets the unread hops
+func (model *Model) MarkRead() {
+	model.unread = 0
+}
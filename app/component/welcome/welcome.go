@@ -4,70 +4,19 @@ import (
 	"math"
 	"strings"
 
+	"github.com/KonstantinGasser/scotty/app/bindings"
 	"github.com/KonstantinGasser/scotty/app/styles"
 	"github.com/charmbracelet/lipgloss"
 )
 
 var (
-	logo = lipgloss.NewStyle().
-		MarginBottom(2).
-		Render(
-			strings.Join([]string{
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4C94")).Render(
-					"███████╗ ██████╗ ██████╗ ████████╗████████╗██╗   ██╗",
-				),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#EF46AC")).Render(
-					"██╔════╝██╔════╝██╔═══██╗╚══██╔══╝╚══██╔══╝╚██╗ ██╔╝",
-				),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#D840C0")).Render(
-					"███████╗██║     ██║   ██║   ██║      ██║    ╚████╔╝ ",
-				),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#BE38D5")).Render(
-					"╚════██║██║     ██║   ██║   ██║      ██║     ╚██╔╝ ",
-				),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#BE38D5")).Render(
-					"███████║╚██████╗╚██████╔╝   ██║      ██║      ██║",
-				),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#9F2DEB")).Render(
-					"╚══════╝ ╚═════╝ ╚═════╝    ╚═╝      ╚═╝      ╚═╝",
-				),
-			}, "\n"),
-		)
-
-	leaderKeyActions = []string{
-		"follow logs",
-		"browse logs",
-		"query  logs",
-	}
-
-	leaderKeyBindings = []string{
-		styles.Bold.Render("SPC f"),
-		styles.Bold.Render("SPC b"),
-		styles.Bold.Render("SPC s"),
-	}
-
-	followKeyActions = []string{
-		"pause/continue",
-		"scroll down",
-	}
-
-	followKeyBindings = []string{
-		styles.Bold.Render("p"),
-		styles.Bold.Render("g"),
-	}
-
-	browseKeyActions = []string{
-		"select index",
-		"next line",
-		"previous line",
-		"reload buffer",
-	}
-
-	browseKeyBindings = []string{
-		styles.Bold.Render(":"),
-		styles.Bold.Render("j"),
-		styles.Bold.Render("k"),
-		styles.Bold.Render("r"),
+	logoLines = []string{
+		"███████╗ ██████╗ ██████╗ ████████╗████████╗██╗   ██╗",
+		"██╔════╝██╔════╝██╔═══██╗╚══██╔══╝╚══██╔══╝╚██╗ ██╔╝",
+		"███████╗██║     ██║   ██║   ██║      ██║    ╚████╔╝ ",
+		"╚════██║██║     ██║   ██║   ██║      ██║     ╚██╔╝ ",
+		"███████║╚██████╗╚██████╔╝   ██║      ██║      ██║",
+		"╚══════╝ ╚═════╝ ╚═════╝    ╚═╝      ╚═╝      ╚═╝",
 	}
 
 	useageFormats = []string{
@@ -76,8 +25,8 @@ var (
 	}
 
 	useageCmds = []string{
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#62fcaf")).Render("go run -race my/awesome/app.go 2>&1 | beam navigation_service"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#62fcaf")).Render("cat uss_enterprise_engine_logs.log | beam -d engine_service"),
+		"go run -race my/awesome/app.go 2>&1 | beam navigation_service",
+		"cat uss_enterprise_engine_logs.log | beam -d engine_service",
 	}
 )
 
@@ -94,24 +43,63 @@ func New(width int, height int) *Model {
 	}
 }
 
+// logo renders the logo with the gradient of the active theme
+func logo() string {
+	gradient := styles.Current().Logo
+
+	lines := make([]string, len(logoLines))
+	for i, line := range logoLines {
+		var color lipgloss.Color
+		if len(gradient) > 0 {
+			color = gradient[i*len(gradient)/len(logoLines)]
+		}
+		lines[i] = lipgloss.NewStyle().Foreground(color).Render(line)
+	}
+
+	return lipgloss.NewStyle().
+		MarginBottom(2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// reference lists the descriptions and keys of the actions
+// of a scope as bound in the active keymap. Actions starting
+// a sequence are left out as their keys are part of the others.
+func reference(scope string) (actions []string, keys []string) {
+	for _, binding := range bindings.Reference("") {
+		if binding.Scope() != scope || binding.Prefix || binding.Keys == "" {
+			continue
+		}
+		actions = append(actions, binding.Description)
+		keys = append(keys, styles.Bold.Render(binding.Keys))
+	}
+	return actions, keys
+}
+
 func (m *Model) View() string {
 
+	logo := logo()
+	cmdStyle := lipgloss.NewStyle().Foreground(styles.Current().Command)
+
 	betweenSmall := int(float64(m.width) * 0.35)
 	betweenMedium := int(float64(m.width) * 0.4)
 
+	leaderActions, leaderKeys := reference("global")
+	followActions, followKeys := reference("follow")
+	browseActions, browseKeys := reference("browse")
+
 	leaderBindings := lipgloss.JoinVertical(lipgloss.Center,
-		lipgloss.PlaceHorizontal(betweenSmall, lipgloss.Center, lipgloss.NewStyle().Render("Leader keys")),
-		styles.SpaceBetween(betweenSmall, leaderKeyActions, leaderKeyBindings, "."),
+		lipgloss.PlaceHorizontal(betweenSmall, lipgloss.Center, lipgloss.NewStyle().Render("Global keys")),
+		styles.SpaceBetween(betweenSmall, leaderActions, leaderKeys, "."),
 	)
 
 	followBindings := lipgloss.JoinVertical(lipgloss.Center,
 		lipgloss.PlaceHorizontal(betweenSmall, lipgloss.Center, lipgloss.NewStyle().Render("Follow tab")),
-		styles.SpaceBetween(betweenSmall, followKeyActions, followKeyBindings, "."),
+		styles.SpaceBetween(betweenSmall, followActions, followKeys, "."),
 	)
 
 	browseBindings := lipgloss.JoinVertical(lipgloss.Center,
 		lipgloss.PlaceHorizontal(betweenSmall, lipgloss.Center, lipgloss.NewStyle().Render("Browse tab")),
-		styles.SpaceBetween(betweenSmall, browseKeyActions, browseKeyBindings, "."),
+		styles.SpaceBetween(betweenSmall, browseActions, browseKeys, "."),
 	)
 
 	usageStderr := styles.SpaceBetween(betweenMedium, useageFormats[0:1], []string{".", "."}, ".")
@@ -156,9 +144,9 @@ func (m *Model) View() string {
 			lipgloss.JoinVertical(lipgloss.Left,
 				lipgloss.PlaceHorizontal(betweenMedium, lipgloss.Center, lipgloss.NewStyle().Render("Usage")),
 				usageStderr,
-				styles.FloatRight(betweenMedium, useageCmds[0]),
+				styles.FloatRight(betweenMedium, cmdStyle.Render(useageCmds[0])),
 				usageStdout,
-				styles.FloatRight(betweenMedium, useageCmds[1]),
+				styles.FloatRight(betweenMedium, cmdStyle.Render(useageCmds[1])),
 			),
 		),
 	)
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"github.com/KonstantinGasser/scotty/app/component/browsing"
+	"github.com/KonstantinGasser/scotty/app/component/querying"
+	"github.com/KonstantinGasser/scotty/app/component/tailing"
+	"github.com/KonstantinGasser/scotty/app/component/tracing"
+	"github.com/KonstantinGasser/scotty/app/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxBeamPanes limits the panes shown by
+	// splitting the content by beam
+	maxBeamPanes = 4
+)
+
+var tabTitles = map[int]string{
+	tabFollow: "follow",
+	tabBrowse: "browse",
+	tabQuery:  "query",
+	tabDocs:   "docs",
+}
+
+// splitPanes divides the content into the active tab and a second
+// tab (browse next to follow, follow next to anything else). Splitting
+// in the current direction again shows the focused pane only.
+func (app *App) splitPanes(split styles.Split) tea.Cmd {
+	switch {
+	case app.split == split && len(app.beamPanes) == 0:
+		app.closePanes()
+	case app.split != styles.SplitNone && len(app.beamPanes) == 0:
+		app.split = split
+	default:
+		app.closePanes()
+		companion := tabFollow
+		if app.activeTab == tabFollow {
+			companion = tabBrowse
+		}
+		app.split = split
+		app.panes = []int{app.activeTab, companion}
+		app.focus = 0
+		app.layout()
+		if companion == tabBrowse {
+			app.components[tabBrowse], _ = app.components[tabBrowse].Update(browsing.RequestInitialView())
+		}
+		return app.modeOfTab()
+	}
+
+	app.layout()
+	return app.modeOfTab()
+}
+
+// splitBeams shows one follow pane per connected beam (in the
+// order of the footer) or the follow tab only if already split
+// by beam. Each pane has its own pager over the shared buffer.
+func (app *App) splitBeams() tea.Cmd {
+	if len(app.beamPanes) > 0 {
+		app.closePanes()
+		app.activeTab = tabFollow
+		app.layout()
+		return app.modeOfTab()
+	}
+	if len(app.labels) == 0 {
+		return nil
+	}
+
+	app.closePanes()
+	app.split = styles.SplitHorizontal
+	for i, label := range app.labels {
+		if i >= maxBeamPanes {
+			break
+		}
+		pager := app.logstore.NewPager(0, 0, app.refresh)
+		pager.Only(label)
+
+		key := app.nextKey
+		app.nextKey++
+		app.components[key] = tailing.New(pager).WithColumns(app.columns)
+		app.beamPanes[key] = label
+		app.panes = append(app.panes, key)
+	}
+	app.focus = 0
+	app.activeTab = app.panes[0]
+	app.layout()
+	return app.modeOfTab()
+}
+
+// closePanes shows the focused pane only and
+// drops the components of the beam panes
+func (app *App) closePanes() {
+	if _, ok := app.beamPanes[app.activeTab]; ok {
+		app.activeTab = tabFollow
+	}
+	for key := range app.beamPanes {
+		delete(app.components, key)
+		delete(app.beamPanes, key)
+	}
+	app.split = styles.SplitNone
+	app.panes = nil
+	app.focus = 0
+}
+
+// focusNext moves the focus to the next pane
+func (app *App) focusNext() tea.Cmd {
+	if len(app.panes) == 0 {
+		return nil
+	}
+	return app.focusPane((app.focus + 1) % len(app.panes))
+}
+
+func (app *App) focusPane(i int) tea.Cmd {
+	app.focus = i
+	app.activeTab = app.panes[i]
+	return app.modeOfTab()
+}
+
+// show makes the tab the active one. If split the pane showing
+// the tab is focused or else the focused pane shows the tab.
+func (app *App) show(tab int) {
+	app.activeTab = tab
+	if len(app.panes) == 0 {
+		return
+	}
+
+	for i, key := range app.panes {
+		if key == tab {
+			app.focus = i
+			return
+		}
+	}
+
+	// a beam pane replaced by a tab is gone
+	replaced := app.panes[app.focus]
+	if _, ok := app.beamPanes[replaced]; ok {
+		delete(app.components, replaced)
+		delete(app.beamPanes, replaced)
+	}
+	app.panes[app.focus] = tab
+	app.layout()
+}
+
+// regions returns the regions of the panes
+// within the content of the grid
+func (app *App) regions() []styles.Region {
+	return app.grid.Content.Split(app.split, len(app.panes))
+}
+
+// layout sends each component the dimensions it is shown
+// with: the body of its pane or the full content otherwise
+func (app *App) layout() {
+	if !app.ready {
+		return
+	}
+
+	dims := make(map[int]styles.Dimensions, len(app.components))
+	for key := range app.components {
+		dims[key] = app.grid.Content.Dims()
+	}
+	for i, region := range app.regions() {
+		if i < len(app.panes) {
+			dims[app.panes[i]] = region.Body()
+		}
+	}
+
+	for key, comp := range app.components {
+		app.components[key], _ = comp.Update(dims[key])
+	}
+	app.bookmarkPanel.Update(app.grid.Content.Dims())
+}
+
+// follow passes the message to all components following
+// the logs: the follow tab, beam panes, query and trace tabs
+func (app *App) follow(msg tea.Msg) {
+	app.components[tabFollow], _ = app.components[tabFollow].Update(msg)
+	for key := range app.beamPanes {
+		app.components[key], _ = app.components[key].Update(msg)
+	}
+	for _, key := range app.tabs {
+		switch app.components[key].(type) {
+		case *querying.Model, *tracing.Model:
+			app.components[key], _ = app.components[key].Update(msg)
+		}
+	}
+}
+
+// paneAt returns the pane at the cell
+// (x, y) of the content
+func (app *App) paneAt(x, y int) (int, styles.Region, bool) {
+	for i, region := range app.regions() {
+		if i < len(app.panes) && region.Contains(x, y) {
+			return i, region, true
+		}
+	}
+	return 0, styles.Region{}, false
+}
+
+// viewPanes renders the views of all panes
+func (app App) viewPanes() string {
+	panes := make([]styles.Pane, len(app.panes))
+	for i, key := range app.panes {
+		panes[i] = styles.Pane{Title: app.title(key), View: app.components[key].View()}
+	}
+	return styles.Panes(app.split, app.regions(), panes, app.focus)
+}
+
+// mousePane focuses the pane the event occurred in and passes
+// the event with rows and columns relative to the pane's body
+func (app *App) mousePane(msg tea.MouseMsg) tea.Cmd {
+	i, region, ok := app.paneAt(msg.X, msg.Y)
+	if !ok {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if i != app.focus {
+		cmd = app.focusPane(i)
+	}
+
+	msg.X -= region.X
+	msg.Y -= region.Y + (region.Height() - region.Body().Height())
+	// the title only focuses the pane
+	if msg.Y < 0 {
+		return cmd
+	}
+
+	key := app.panes[i]
+	var paneCmd tea.Cmd
+	app.components[key], paneCmd = app.components[key].Update(msg)
+	return tea.Batch(cmd, paneCmd)
+}
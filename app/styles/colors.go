@@ -1,17 +1,14 @@
 package styles
 
 import (
-	"fmt"
-	"io/ioutil"
-	"math/rand"
-	"os"
-	"path/filepath"
-	"time"
+	"hash/fnv"
+	"math"
 
 	"github.com/charmbracelet/lipgloss"
-	"gopkg.in/yaml.v2"
 )
 
+// Color holds the base colors of a Theme. The colors can
+// be overwritten individually in the configuration file.
 type Color struct {
 	Border    lipgloss.Color `yaml:"border"`
 	Error     lipgloss.Color `yaml:"error"`
@@ -19,24 +16,32 @@ type Color struct {
 	Light     lipgloss.Color `yaml:"light"`
 }
 
-type Config struct {
-	Colors Color `yaml:"colors"`
-}
-
-var DefaultColor = Color{
-	Border:    lipgloss.Color("97"),
-	Error:     lipgloss.Color("31"),
-	Light:     lipgloss.Color("7"),
-	Highlight: lipgloss.Color("11"),
-}
+// minContrast is the WCAG contrast ratio required
+// for normal sized text
+const minContrast = 4.5
 
-func RandColor() (lipgloss.Color, lipgloss.Color) {
-	// including extended ANSI Grayscale color reach from 0-255
-	// see https://github.com/muesli/termenv which is used for lipgloss
+// BeamColor returns the color for a beam label. The color
+// is derived from a hash of the label and as such the same label
+// is always colored the same - also between restarts of scotty.
+func BeamColor(label string) lipgloss.Color {
+	h := fnv.New32a()
+	h.Write([]byte(label))
 
-	color := lipgloss.Color(fmt.Sprint(rand.Intn(256)))
+	palette := current.Palette
+	return palette[h.Sum32()%uint32(len(palette))]
+}
 
-	return color, InverseColor(color)
+// NextColor returns the color following c in the palette of
+// the active theme. If c is not part of the palette the first
+// color is returned.
+func NextColor(c lipgloss.Color) lipgloss.Color {
+	palette := current.Palette
+	for i, color := range palette {
+		if color == c {
+			return palette[(i+1)%len(palette)]
+		}
+	}
+	return palette[0]
 }
 
 func InverseColor(c lipgloss.Color) lipgloss.Color {
@@ -52,42 +57,26 @@ func InverseColor(c lipgloss.Color) lipgloss.Color {
 	return inverse
 }
 
-func LoadConfig() (*Config, error) {
-	var cfg Config
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-
-	configPath := filepath.Join(homeDir, ".scotty", "config.yaml")
-	yamlFile, err := os.Open(configPath)
-	if err != nil {
-		// fallback to default colors if config file not found
-		return &Config{Colors: DefaultColor}, nil
+// contrast computes the WCAG contrast ratio between two colors.
+// The ratio ranges from 1 (no contrast) to 21 (black on white).
+func contrast(a, b lipgloss.TerminalColor) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
 	}
-	defer yamlFile.Close()
-
-	yamlBytes, err := ioutil.ReadAll(yamlFile)
-	if err != nil {
-		return nil, err
-	}
-
-	if err = yaml.Unmarshal(yamlBytes, &cfg); err != nil {
-		return nil, err
-	}
-
-	return &cfg, nil
+	return (la + 0.05) / (lb + 0.05)
 }
 
-func init() {
-	rand.Seed(time.Now().Unix())
+func luminance(c lipgloss.TerminalColor) float64 {
+	r, g, b, _ := c.RGBA()
 
-	config, err := LoadConfig()
-	if err != nil {
-		fmt.Printf("error loading config file: %v\n", err)
-		return
+	var channel = func(v uint32) float64 {
+		s := float64(v) / 0xffff
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
 	}
 
-	DefaultColor = config.Colors
-}
\ No newline at end of file
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
@@ -0,0 +1,39 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPaletteContrast(t *testing.T) {
+
+	for _, theme := range []Theme{DarkTheme, LightTheme, HighContrastTheme} {
+		backgrounds := []lipgloss.Color{
+			theme.Background, // plain terminal
+			theme.Footer,
+		}
+
+		for _, color := range theme.Palette {
+			for _, bg := range backgrounds {
+				if ratio := contrast(color, bg); ratio < minContrast {
+					t.Fatalf("[%s] palette color %s has a contrast of %.2f on %s; wanted at least %.2f", theme.Name, color, ratio, bg, minContrast)
+				}
+			}
+		}
+	}
+}
+
+func TestBeamColorIsStable(t *testing.T) {
+
+	labels := []string{"checkout-svc", "payment-svc", "a", ""}
+
+	for _, label := range labels {
+		first := BeamColor(label)
+		for i := 0; i < 10; i++ {
+			if got := BeamColor(label); got != first {
+				t.Fatalf("color for %q changed from %s to %s", label, first, got)
+			}
+		}
+	}
+}
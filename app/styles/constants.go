@@ -6,13 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/ansi"
-)
-
-var (
-	BgFooter = lipgloss.AdaptiveColor{
-		Light: "#2c323d",
-		Dark:  "#2c323d",
-	}
+	"github.com/muesli/reflow/truncate"
 )
 
 type Grid struct {
@@ -56,9 +50,7 @@ func NewGrid(width int, height int) Grid {
 				height: footerLineDefaultHeight,
 			},
 			style: lipgloss.NewStyle().
-				MarginTop(1).
-				Background(BgFooter).
-				Width(width),
+				MarginTop(footerMarginTop),
 		},
 	}
 }
@@ -75,6 +67,12 @@ func (grid *Grid) Adjust(width int, height int) {
 	grid.Content.height = height - (grid.TabLine.height + grid.FooterLine.height)
 }
 
+// FooterRow returns the row of the
+// terminal the footer is rendered in
+func (grid Grid) FooterRow() int {
+	return grid.TabLine.height + grid.Content.height + footerMarginTop
+}
+
 type Dimensions struct {
 	width  int
 	height int
@@ -90,9 +88,25 @@ func (dim Dimensions) Height() int {
 
 func (dim Dimensions) Dims() Dimensions { return dim }
 
+// NewDimensions allows components to pass a
+// part of their dimensions to nested components
+func NewDimensions(width int, height int) Dimensions {
+	return Dimensions{width: width, height: height}
+}
+
 const (
-	tabLineDefaultHeight    = 0
+	// tabLineDefaultHeight is the tab bar
+	// and the empty line below it
+	tabLineDefaultHeight    = 2
 	footerLineDefaultHeight = 2
+	// footerMarginTop is the empty line
+	// between the content and the footer
+	footerMarginTop = 1
+	// paneTitleHeight is the row naming a pane
+	// and paneDividerWidth the column between
+	// panes side by side
+	paneTitleHeight  = 1
+	paneDividerWidth = 1
 )
 
 type TabLine struct {
@@ -109,13 +123,92 @@ type Content struct {
 	style lipgloss.Style
 }
 
+// Split is the direction the content
+// is divided into panes
+type Split int
+
+const (
+	SplitNone Split = iota
+	// SplitHorizontal stacks the panes
+	SplitHorizontal
+	// SplitVertical places the panes side by side
+	SplitVertical
+)
+
+// Region is a pane of the content starting at the
+// column X and the row Y (relative to the content).
+// The first row of a region is the title of the pane.
+type Region struct {
+	Dimensions
+	X, Y int
+}
+
+// Body returns the dimensions of the
+// region below the title
+func (region Region) Body() Dimensions {
+	return Dimensions{
+		width:  region.width,
+		height: clamp2(region.height-paneTitleHeight, 0, region.height),
+	}
+}
+
+// Contains reports whether the cell (x, y)
+// of the content is part of the region
+func (region Region) Contains(x, y int) bool {
+	return x >= region.X && x < region.X+region.width &&
+		y >= region.Y && y < region.Y+region.height
+}
+
+// Split divides the content into n regions of about the same
+// size. Panes side by side are separated by a divider column.
+func (content Content) Split(split Split, n int) []Region {
+	if n <= 1 || split == SplitNone {
+		return []Region{{Dimensions: content.Dimensions}}
+	}
+
+	size := content.height
+	if split == SplitVertical {
+		size = content.width - (n - 1)
+	}
+
+	regions := make([]Region, n)
+	var at int
+	for i := range regions {
+		// the last region takes the rest
+		length := size / n
+		if i == n-1 {
+			length = size - (n-1)*(size/n)
+		}
+
+		switch split {
+		case SplitVertical:
+			regions[i] = Region{Dimensions: Dimensions{width: length, height: content.height}, X: at}
+			at += length + paneDividerWidth
+		default:
+			regions[i] = Region{Dimensions: Dimensions{width: content.width, height: length}, Y: at}
+			at += length
+		}
+	}
+	return regions
+}
+
 type FooterLine struct {
 	Dimensions
 	style lipgloss.Style
 }
 
+// Render renders the content in a single row. Content
+// wider than the footer is cut instead of wrapped as the
+// grid only reserves a single row for it.
 func (footer *FooterLine) Render(content string) string {
-	return footer.style.Render(content)
+	if footer.width > 0 {
+		content = truncate.String(content, uint(footer.width))
+	}
+	return footer.style.Copy().
+		Background(current.Footer).
+		Width(footer.width).
+		MaxWidth(footer.width).
+		Render(content)
 }
 
 // SpaceBetween performs a row alignment on the left and
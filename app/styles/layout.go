@@ -42,7 +42,7 @@ func Overlay(
 	if shadow {
 		var shadowbg string = ""
 		shadowchar := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#333333")).
+			Foreground(current.Shadow).
 			Render("░")
 		for i := 0; i <= fgHeight; i++ {
 			if i == 0 {
@@ -0,0 +1,95 @@
+package styles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/ansi"
+	"github.com/muesli/reflow/truncate"
+)
+
+// Pane is the title and the view of a
+// region of the content
+type Pane struct {
+	Title string
+	View  string
+}
+
+// Panes renders the panes into the regions of the split. Each
+// view is cut or padded to its region such that the panes line
+// up. The title of the focused pane is highlighted.
+func Panes(split Split, regions []Region, panes []Pane, focused int) string {
+
+	blocks := make([][]string, len(panes))
+	for i, pane := range panes {
+		if i >= len(regions) {
+			break
+		}
+		blocks[i] = pane.render(regions[i], i == focused)
+	}
+
+	if split != SplitVertical {
+		var lines []string
+		for _, block := range blocks {
+			lines = append(lines, block...)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	divider := lipgloss.NewStyle().Foreground(current.Border).Render("│")
+
+	var height int
+	for _, block := range blocks {
+		if len(block) > height {
+			height = len(block)
+		}
+	}
+	lines := make([]string, height)
+	for row := range lines {
+		cells := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			if row < len(block) {
+				cells = append(cells, block[row])
+			}
+		}
+		lines[row] = strings.Join(cells, divider)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render returns the exact lines of the pane in the region
+func (pane Pane) render(region Region, focused bool) []string {
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(current.Tab).
+		Width(region.width)
+	if focused {
+		titleStyle = titleStyle.
+			Foreground(current.TabActiveText).
+			Background(current.TabActive)
+	}
+
+	lines := make([]string, 0, region.height)
+	lines = append(lines, titleStyle.Render(fitWidth(" "+pane.Title, region.width)))
+
+	body := region.Body()
+	view := strings.Split(pane.View, "\n")
+	for row := 0; row < body.height; row++ {
+		var line string
+		if row < len(view) {
+			line = view[row]
+		}
+		lines = append(lines, fitWidth(line, body.width))
+	}
+	return lines
+}
+
+// fitWidth cuts or pads the line to the width
+func fitWidth(line string, width int) string {
+	// pagers fill empty lines with NUL
+	line = strings.ReplaceAll(line, "\000", "")
+	if ansi.PrintableRuneWidth(line) > width {
+		line = truncate.String(line, uint(width))
+	}
+	return line + strings.Repeat(" ", clamp2(width-ansi.PrintableRuneWidth(line), 0, width))
+}
@@ -0,0 +1,74 @@
+package styles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/muesli/ansi"
+)
+
+func TestSplit(t *testing.T) {
+
+	grid := NewGrid(81, 23)
+
+	for _, tc := range []struct {
+		split Split
+		n     int
+		want  []Region
+	}{
+		{
+			split: SplitHorizontal,
+			n:     2,
+			want: []Region{
+				{Dimensions: Dimensions{width: 81, height: 9}},
+				{Dimensions: Dimensions{width: 81, height: 10}, Y: 9},
+			},
+		},
+		{
+			split: SplitVertical,
+			n:     2,
+			want: []Region{
+				{Dimensions: Dimensions{width: 40, height: 19}},
+				{Dimensions: Dimensions{width: 40, height: 19}, X: 41},
+			},
+		},
+		{
+			split: SplitNone,
+			n:     2,
+			want: []Region{
+				{Dimensions: Dimensions{width: 81, height: 19}},
+			},
+		},
+	} {
+		regions := grid.Content.Split(tc.split, tc.n)
+		if len(regions) != len(tc.want) {
+			t.Fatalf("[split %d] wanted %d regions, got: %+v", tc.split, len(tc.want), regions)
+		}
+		for i := range regions {
+			if regions[i] != tc.want[i] {
+				t.Fatalf("[split %d] wanted region %d: %+v, got: %+v", tc.split, i, tc.want[i], regions[i])
+			}
+		}
+	}
+}
+
+func TestPanesLineUp(t *testing.T) {
+
+	grid := NewGrid(41, 8)
+	regions := grid.Content.Split(SplitVertical, 2)
+
+	view := Panes(SplitVertical, regions, []Pane{
+		{Title: "follow", View: "a line much too long for the pane to show\n\000"},
+		{Title: "browse", View: "short"},
+	}, 0)
+
+	lines := strings.Split(view, "\n")
+	if len(lines) != grid.Content.Height() {
+		t.Fatalf("wanted %d lines, got: %d", grid.Content.Height(), len(lines))
+	}
+	for i, line := range lines {
+		if w := ansi.PrintableRuneWidth(line); w != grid.Content.Width() {
+			t.Fatalf("wanted line %d to be %d wide, got: %d (%q)", i, grid.Content.Width(), w, line)
+		}
+	}
+}
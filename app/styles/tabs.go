@@ -2,24 +2,8 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-var (
-	fgDefault = lipgloss.AdaptiveColor{
-		Light: "43",
-		Dark:  "43",
-	}
-
-	fgActive = lipgloss.AdaptiveColor{
-		Light: "0",
-		Dark:  "0",
-	}
-
-	tabDefaultStyle = lipgloss.NewStyle().
-			MarginRight(1).
-			Foreground(fgDefault)
-
-	tabActiveStyle = tabDefaultStyle.Copy().
-			Foreground(fgActive).
-			Background(lipgloss.Color("43"))
+const (
+	tabMargin = 1
 )
 
 type Tabs struct {
@@ -40,6 +24,27 @@ func NewTabs(active int, lables ...string) *Tabs {
 	return tabs
 }
 
+// SetLabels replaces the labels of the tabs
+func (tabs *Tabs) SetLabels(lables ...string) {
+	tabs.lables = lables
+	tabs.build()
+}
+
+// At returns the index of the tab
+// rendered at the column x
+func (tabs *Tabs) At(x int) (int, bool) {
+	var at int
+	for i, label := range tabs.lables {
+		width := lipgloss.Width(label)
+		if x >= at && x < at+width {
+			return i, true
+		}
+		// tabs are separated by their margin
+		at += width + tabMargin
+	}
+	return 0, false
+}
+
 func (tabs *Tabs) SetActive(index int) {
 	if index > len(tabs.lables)-1 {
 		return
@@ -50,6 +55,15 @@ func (tabs *Tabs) SetActive(index int) {
 }
 
 func (tabs *Tabs) build() {
+
+	tabDefaultStyle := lipgloss.NewStyle().
+		MarginRight(tabMargin).
+		Foreground(current.Tab)
+
+	tabActiveStyle := tabDefaultStyle.Copy().
+		Foreground(current.TabActiveText).
+		Background(current.TabActive)
+
 	var items = make([]string, len(tabs.lables))
 	for i := range items {
 		if i == tabs.active {
@@ -70,6 +84,11 @@ var (
 	tabsStyle = lipgloss.NewStyle().PaddingBottom(1)
 )
 
+// Restyle rebuilds the tabs with the active theme
+func (tabs *Tabs) Restyle() {
+	tabs.build()
+}
+
 func (tabs Tabs) View() string {
 	return tabsStyle.
 		Render(tabs.view)
@@ -0,0 +1,290 @@
+package styles
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Modes holds the background colors of the
+// mode indicator in the footer
+type Modes struct {
+	// Text is the foreground of the mode indicator
+	Text      lipgloss.Color `yaml:"text"`
+	Following lipgloss.Color `yaml:"following"`
+	Browsing  lipgloss.Color `yaml:"browsing"`
+	Paused    lipgloss.Color `yaml:"paused"`
+	Command   lipgloss.Color `yaml:"command"`
+	Input     lipgloss.Color `yaml:"input"`
+}
+
+// Theme holds every color used by the UI. An empty
+// color renders with the terminal's default color.
+type Theme struct {
+	Name string `yaml:"-"`
+	// Extends names the theme a custom theme is based on.
+	// Colors not set by the custom theme are taken from it.
+	Extends string `yaml:"extends"`
+	// Background is the terminal background the theme is
+	// made for. Beam colors are picked to be readable on it.
+	Background lipgloss.Color `yaml:"background"`
+	Color      `yaml:",inline"`
+	Footer     lipgloss.Color `yaml:"footer"`
+	Modes      Modes          `yaml:"modes"`
+	// Tab is the color of inactive tabs while the
+	// active tab is rendered as TabActiveText on TabActive
+	Tab           lipgloss.Color `yaml:"tab"`
+	TabActive     lipgloss.Color `yaml:"tab_active"`
+	TabActiveText lipgloss.Color `yaml:"tab_active_text"`
+	Modal         lipgloss.Color `yaml:"modal"`
+	Shadow        lipgloss.Color `yaml:"shadow"`
+	// Command colors the example commands
+	// on the welcome screen
+	Command lipgloss.Color `yaml:"command"`
+	// Logo is the gradient of the logo
+	// on the welcome screen (top to bottom)
+	Logo []lipgloss.Color `yaml:"logo"`
+	// Palette is the set of colors beams are colored with
+	Palette []lipgloss.Color `yaml:"palette"`
+}
+
+// EachColor calls fn with the yaml name and a pointer to each
+// single color of the theme (Logo and Palette excluded).
+func (theme *Theme) EachColor(fn func(name string, color *lipgloss.Color)) {
+	for _, c := range []struct {
+		name  string
+		color *lipgloss.Color
+	}{
+		{"background", &theme.Background},
+		{"border", &theme.Border},
+		{"error", &theme.Error},
+		{"highlight", &theme.Highlight},
+		{"light", &theme.Light},
+		{"footer", &theme.Footer},
+		{"modes.text", &theme.Modes.Text},
+		{"modes.following", &theme.Modes.Following},
+		{"modes.browsing", &theme.Modes.Browsing},
+		{"modes.paused", &theme.Modes.Paused},
+		{"modes.command", &theme.Modes.Command},
+		{"modes.input", &theme.Modes.Input},
+		{"tab", &theme.Tab},
+		{"tab_active", &theme.TabActive},
+		{"tab_active_text", &theme.TabActiveText},
+		{"modal", &theme.Modal},
+		{"shadow", &theme.Shadow},
+		{"command", &theme.Command},
+	} {
+		fn(c.name, c.color)
+	}
+}
+
+// Extend returns the theme with all colors not set
+// by the theme taken from base
+func (theme Theme) Extend(base Theme) Theme {
+
+	var inherited []lipgloss.Color
+	base.EachColor(func(_ string, color *lipgloss.Color) {
+		inherited = append(inherited, *color)
+	})
+
+	var i int
+	theme.EachColor(func(_ string, color *lipgloss.Color) {
+		if *color == "" {
+			*color = inherited[i]
+		}
+		i++
+	})
+
+	if len(theme.Logo) == 0 {
+		theme.Logo = base.Logo
+	}
+	if len(theme.Palette) == 0 {
+		theme.Palette = base.Palette
+	}
+
+	return theme
+}
+
+var (
+	DarkTheme = Theme{
+		Name:       "dark",
+		Background: "#000000",
+		Color: Color{
+			Border:    "97",
+			Error:     "31",
+			Light:     "7",
+			Highlight: "11",
+		},
+		Footer: "#2c323d",
+		Modes: Modes{
+			Text:      "#ffffff",
+			Following: "#98c379",
+			Browsing:  "#98c378",
+			Paused:    "#ff9640",
+			Command:   "54",
+			Input:     "54",
+		},
+		Tab:           "43",
+		TabActive:     "43",
+		TabActiveText: "0",
+		Shadow:        "#333333",
+		Command:       "#62fcaf",
+		Logo:          []lipgloss.Color{"#FF4C94", "#EF46AC", "#D840C0", "#BE38D5", "#BE38D5", "#9F2DEB"},
+		Palette: []lipgloss.Color{
+			"#ff6ba8", "#62fcaf", "#61afef", "#e5c07b",
+			"#d38aea", "#56b6c2", "#98c379", "#ff9640",
+			"#f47a8b", "#89ddff", "#c3e88d", "#f78c6c",
+			"#82aaff", "#ffcb6b", "#b392f0", "#7fdbca",
+		},
+	}
+
+	LightTheme = Theme{
+		Name:       "light",
+		Background: "#ffffff",
+		Color: Color{
+			Border:    "#6f42c1",
+			Error:     "#d1242f",
+			Light:     "#57606a",
+			Highlight: "#9a6700",
+		},
+		Footer: "#eef0f3",
+		Modes: Modes{
+			Text:      "#ffffff",
+			Following: "#1a7f37",
+			Browsing:  "#0969da",
+			Paused:    "#bc4c00",
+			Command:   "#8250df",
+			Input:     "#8250df",
+		},
+		Tab:           "#0969da",
+		TabActive:     "#0969da",
+		TabActiveText: "#ffffff",
+		Modal:         "#6f42c1",
+		Shadow:        "#d0d7de",
+		Command:       "#1a7f37",
+		Logo:          []lipgloss.Color{"#d6246e", "#c91f87", "#b01a9e", "#9416b4", "#9416b4", "#7412c9"},
+		Palette: []lipgloss.Color{
+			"#b4235f", "#08704a", "#0b5cad", "#8a5a00",
+			"#8a3fb0", "#0e6f7a", "#44700f", "#a04300",
+			"#b3263e", "#005f87", "#5c6e00", "#a33b1f",
+			"#3a55b4", "#7d5f00", "#6b3fb3", "#0f6e62",
+		},
+	}
+
+	HighContrastTheme = Theme{
+		Name:       "high-contrast",
+		Background: "#000000",
+		Color: Color{
+			Border:    "#ffffff",
+			Error:     "#ff5f5f",
+			Light:     "#ffffff",
+			Highlight: "#ffff00",
+		},
+		Footer: "#000000",
+		Modes: Modes{
+			Text:      "#000000",
+			Following: "#00ff00",
+			Browsing:  "#00ffff",
+			Paused:    "#ffaf00",
+			Command:   "#ff87ff",
+			Input:     "#ff87ff",
+		},
+		Tab:           "#ffffff",
+		TabActive:     "#ffff00",
+		TabActiveText: "#000000",
+		Modal:         "#ffffff",
+		Shadow:        "#808080",
+		Command:       "#00ff00",
+		Logo:          []lipgloss.Color{"#ffffff", "#ffffff", "#ffffff", "#ffffff", "#ffffff", "#ffffff"},
+		Palette: []lipgloss.Color{
+			"#ffffff", "#ffff00", "#00ffff", "#00ff00",
+			"#ff87ff", "#ffaf00", "#87d7ff", "#d7ff87",
+			"#ff8787", "#afafff", "#5fffaf", "#ffd7af",
+		},
+	}
+)
+
+var (
+	// themes holds the built-in and all registered
+	// custom themes by their name
+	themes = map[string]Theme{
+		DarkTheme.Name:         DarkTheme,
+		LightTheme.Name:        LightTheme,
+		HighContrastTheme.Name: HighContrastTheme,
+	}
+	// current is the theme the UI is rendered with
+	current = DarkTheme
+)
+
+// Current returns the active theme
+func Current() Theme {
+	return current
+}
+
+// SetTheme makes the theme the active theme. Components
+// render with the new theme once they received ThemeChanged.
+func SetTheme(theme Theme) {
+	current = theme
+}
+
+// ThemeChanged is send to all components after
+// the active theme has been changed
+type ThemeChanged struct{}
+
+// Register adds a custom theme. The theme is extended by the
+// theme it names in Extends (default: dark) which must either
+// be built-in or registered before.
+func Register(theme Theme) error {
+	if theme.Name == "" {
+		return fmt.Errorf("theme must have a name")
+	}
+
+	extends := theme.Extends
+	if extends == "" {
+		extends = DarkTheme.Name
+	}
+
+	base, ok := themes[extends]
+	if !ok {
+		return fmt.Errorf("theme %q extends unknown theme %q", theme.Name, extends)
+	}
+
+	themes[theme.Name] = theme.Extend(base)
+	return nil
+}
+
+// LookupTheme returns the built-in or registered theme
+func LookupTheme(name string) (Theme, bool) {
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames returns the names of all themes in
+// alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NextTheme returns the theme following the active
+// theme in the order of ThemeNames
+func NextTheme() Theme {
+	names := ThemeNames()
+	for i, name := range names {
+		if name == current.Name {
+			return themes[names[(i+1)%len(names)]]
+		}
+	}
+	return DarkTheme
+}
+
+// IsLight reports whether the theme is
+// made for a light terminal background
+func (theme Theme) IsLight() bool {
+	return luminance(theme.Background) > 0.5
+}
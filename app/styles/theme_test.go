@@ -0,0 +1,57 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRegisterExtendsTheme(t *testing.T) {
+
+	custom := Theme{
+		Name:    "solarized-ish",
+		Extends: LightTheme.Name,
+		Footer:  "#fdf6e3",
+		Palette: []lipgloss.Color{"#268bd2"},
+	}
+
+	if err := Register(custom); err != nil {
+		t.Fatalf("unable to register theme: %v", err)
+	}
+	defer delete(themes, custom.Name)
+
+	theme, ok := LookupTheme(custom.Name)
+	if !ok {
+		t.Fatalf("wanted theme %q to be registered", custom.Name)
+	}
+
+	if theme.Footer != "#fdf6e3" {
+		t.Fatalf("wanted footer of custom theme: %s - got: %s", "#fdf6e3", theme.Footer)
+	}
+	if theme.Modes.Browsing != LightTheme.Modes.Browsing {
+		t.Fatalf("wanted browsing mode from base theme: %s - got: %s", LightTheme.Modes.Browsing, theme.Modes.Browsing)
+	}
+	if len(theme.Logo) != len(LightTheme.Logo) || len(theme.Palette) != 1 {
+		t.Fatalf("wanted logo from base theme and own palette - got: %v, %v", theme.Logo, theme.Palette)
+	}
+
+	if err := Register(Theme{Name: "broken", Extends: "unknown"}); err == nil {
+		t.Fatalf("wanted error for unknown base theme - got nil")
+	}
+}
+
+func TestBuiltInThemesAreComplete(t *testing.T) {
+
+	for _, theme := range []Theme{DarkTheme, LightTheme, HighContrastTheme} {
+		if len(theme.Palette) == 0 || len(theme.Logo) == 0 {
+			t.Fatalf("[%s] wanted logo and palette to be set", theme.Name)
+		}
+		theme.EachColor(func(name string, color *lipgloss.Color) {
+			// the dark theme renders modals with the
+			// terminal's default color
+			if *color == "" && !(theme.Name == DarkTheme.Name && name == "modal") {
+				t.Fatalf("[%s] wanted %s to be set", theme.Name, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/KonstantinGasser/scotty/app/component/browsing"
+	"github.com/KonstantinGasser/scotty/app/component/querying"
+	"github.com/KonstantinGasser/scotty/app/component/tailing"
+	"github.com/KonstantinGasser/scotty/app/component/tracing"
+	"github.com/KonstantinGasser/scotty/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// unread is implemented by components counting the
+// logs received while the tab has not been looked at
+type unread interface {
+	Unread() int
+	MarkRead()
+}
+
+// titled is implemented by components
+// naming their tab themselves
+type titled interface {
+	Title() string
+}
+
+// title returns the name of the component
+// shown in the tab bar and the pane title
+func (app *App) title(key int) string {
+	if label, ok := app.beamPanes[key]; ok {
+		return "follow: " + label
+	}
+	if comp, ok := app.components[key].(titled); ok {
+		return comp.Title()
+	}
+	return tabTitles[key]
+}
+
+// addQueryTab appends a tab following
+// the logs matching the query
+func (app *App) addQueryTab(name string, query store.Query) int {
+	follow := tailing.New(app.logstore.NewPager(0, 0, app.refresh)).WithColumns(app.columns)
+
+	key := app.nextKey
+	app.nextKey++
+	app.components[key] = querying.New(name, follow, query)
+	app.tabs = append(app.tabs, key)
+	return key
+}
+
+// addTraceTab appends a tab following the logs having the
+// value in the field. A tab tracing the same value is reused.
+func (app *App) addTraceTab(field string, value string) int {
+	for _, key := range app.tabs {
+		if trace, ok := app.components[key].(*tracing.Model); ok && trace.Traces(field, value) {
+			return key
+		}
+	}
+
+	key := app.nextKey
+	app.nextKey++
+	app.components[key] = tracing.New(app.logstore.NewTrace(field, value))
+	app.tabs = append(app.tabs, key)
+	return key
+}
+
+// closeTab closes the tab of a saved query or a trace and
+// switches to the query tab or the browse tab respectively
+func (app *App) closeTab(key int) tea.Cmd {
+	next := tabQuery
+	switch app.components[key].(type) {
+	case *querying.Model:
+		if key == tabQuery {
+			return app.modeOfTab()
+		}
+	case *tracing.Model:
+		next = tabBrowse
+	default:
+		return app.modeOfTab()
+	}
+
+	for _, pane := range app.panes {
+		if pane == key {
+			app.closePanes()
+			break
+		}
+	}
+	for i, tab := range app.tabs {
+		if tab == key {
+			app.tabs = append(app.tabs[:i], app.tabs[i+1:]...)
+			break
+		}
+	}
+	delete(app.components, key)
+
+	app.activeTab = tabUnset
+	// the browse tab keeps the selected log
+	if next == tabBrowse {
+		app.show(tabBrowse)
+		return app.modeOfTab()
+	}
+	return app.switchTo(next)
+}
+
+// switchTo makes the tab the active one
+// and requests the mode of the tab
+func (app *App) switchTo(key int) tea.Cmd {
+	if app.activeTab == key {
+		return app.modeOfTab()
+	}
+
+	app.show(key)
+	if key == tabBrowse {
+		return tea.Batch(app.modeOfTab(), browsing.RequestInitialView)
+	}
+	return app.modeOfTab()
+}
+
+// updateTabs marks the logs of the visible tabs as read and
+// builds the tab bar with the unread logs of the other tabs
+func (app *App) updateTabs() {
+	if app.headerComponent == nil {
+		return
+	}
+
+	visible := map[int]bool{app.activeTab: true}
+	for _, key := range app.panes {
+		visible[key] = true
+	}
+
+	active := -1
+	labels := make([]string, len(app.tabs))
+	for i, key := range app.tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, app.title(key))
+		if comp, ok := app.components[key].(unread); ok {
+			if visible[key] {
+				comp.MarkRead()
+			} else if n := comp.Unread(); n > 0 {
+				label = fmt.Sprintf(" %d %s (%d) ", i+1, app.title(key), n)
+			}
+		}
+		if key == app.activeTab {
+			active = i
+		}
+		labels[i] = label
+	}
+
+	app.headerComponent.SetLabels(labels...)
+	app.headerComponent.SetActive(active)
+}
+
+// tabAt switches to the tab clicked at the column x
+func (app *App) tabAt(x int) tea.Cmd {
+	i, ok := app.headerComponent.At(x)
+	if !ok || i >= len(app.tabs) {
+		return nil
+	}
+	return app.switchTo(app.tabs[i])
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// whichKey places a popup on top of the content listing the
+// keys which can follow the pending key sequence. The popup is
+// generated from the bindings and as such always lists exactly
+// the keys which are bound.
+func (app App) whichKey(content string) string {
+
+	keys, hints, ok := app.bindings.Pending()
+	if !ok || len(hints) == 0 {
+		return content
+	}
+
+	theme := styles.Current()
+
+	var keyWidth int
+	for _, hint := range hints {
+		if w := lipgloss.Width(hint.Key); w > keyWidth {
+			keyWidth = w
+		}
+	}
+
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Highlight).
+		Width(keyWidth).
+		MarginRight(2)
+
+	rows := []string{
+		lipgloss.NewStyle().Bold(true).Render(strings.Join(keys, " ") + " …"),
+		"",
+	}
+	for _, hint := range hints {
+		rows = append(rows, keyStyle.Render(hint.Key)+hint.Description)
+	}
+
+	popup := lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Border).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	// Overlay keeps the popup within the content hence
+	// it ends up in the bottom right corner
+	return styles.Overlay(app.grid.Content.Width(), app.grid.Content.Height(), popup, content, false)
+}
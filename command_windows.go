@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import "os/exec"
+
+// detach is a no-op as Windows has
+// no process groups to start the command in
+func detach(cmd *exec.Cmd) {}
+
+// terminate kills the command. Processes of its pipe
+// chain are not stopped as there is no process group.
+func terminate(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
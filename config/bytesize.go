@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is an amount of memory which can be written
+// as "256MB", "1.5GB" or "4096" (bytes) in the configuration
+// file and on the command line.
+type ByteSize uint64
+
+var byteUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a size such as 256MB. Units are
+// powers of 1024 and case insensitive.
+func ParseByteSize(raw string) (ByteSize, error) {
+
+	value := strings.ToUpper(strings.TrimSpace(raw))
+
+	unit := ByteSize(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512KB, 256MB or 1GB)", raw)
+	}
+
+	return ByteSize(n * float64(unit)), nil
+}
+
+// String formats the size with the largest unit
+// fitting the size, e.g. 12.3MB
+func (b ByteSize) String() string {
+	for _, u := range byteUnits {
+		if b >= u.size && u.size > 1 {
+			return strconv.FormatFloat(float64(b)/float64(u.size), 'f', 1, 64) + u.suffix
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// Set implements flag.Value
+func (b *ByteSize) Set(raw string) error {
+	parsed, err := ParseByteSize(raw)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+func (b *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	return b.Set(raw)
+}
@@ -0,0 +1,685 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/clipboard"
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/store"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// localFile is looked up in the current working directory
+	// and takes precedence over the file in the home directory
+	localFile = ".scotty.yaml"
+	// homeDir and homeFile make up the path to the
+	// configuration file in the users home directory
+	homeDir  = ".scotty"
+	homeFile = "config.yaml"
+	// bookmarksFile is the file in the home directory
+	// the bookmarks are kept in between sessions
+	bookmarksFile = "bookmarks.json"
+)
+
+// Duration wraps time.Duration so that durations can be
+// written as "50ms" or "1s" in the configuration file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", raw, err)
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// Listener describes a network interface on which
+// scotty accepts connections from beams.
+type Listener struct {
+	Network string `yaml:"network"`
+	Addr    string `yaml:"addr"`
+}
+
+// Beam holds settings applied to a beam identified
+// by its label.
+type Beam struct {
+	Color string `yaml:"color"`
+	// Quota bounds the memory used by the logs of the beam
+	// and overwrites the BeamQuota of the Settings
+	Quota ByteSize `yaml:"quota"`
+	// Format forces the format the logs of the beam are
+	// pretty printed in by the browse view (such as logfmt)
+	// instead of detecting it for each log
+	Format string `yaml:"format"`
+	// ANSI decides what happens to the colors (ANSI escape
+	// sequences) of the beam's logs and overwrites the ANSI
+	// of the Settings
+	ANSI string `yaml:"ansi"`
+	// Collapse decides whether logs repeating the previous
+	// log of the beam are counted as its repeats and
+	// overwrites the Collapse of the Settings
+	Collapse string `yaml:"collapse"`
+}
+
+// Highlight marks any log matching the Pattern
+// with the given Color.
+type Highlight struct {
+	Pattern string `yaml:"pattern"`
+	Color   string `yaml:"color"`
+}
+
+// Query is a named filter which can be reused
+// without typing it again.
+type Query struct {
+	Name   string `yaml:"name"`
+	Filter string `yaml:"filter"`
+}
+
+// Command is a shell command started by scotty
+// once it is ready to accept beams. A typical command
+// pipes an application into beam.
+type Command struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+}
+
+// Time configures how the time of a log is determined
+// and displayed. If Fields are set the time is read from
+// the first field found in the log (JSON or logfmt).
+type Time struct {
+	Fields  []string `yaml:"fields"`
+	Display string   `yaml:"display"`
+}
+
+// Settings are all options which can be set in the
+// configuration file either at the top level or
+// within a profile.
+type Settings struct {
+	Listeners  []Listener              `yaml:"listeners"`
+	Buffer     int                     `yaml:"buffer"`
+	BufferSize ByteSize                `yaml:"buffer_bytes"`
+	BeamQuota  ByteSize                `yaml:"beam_quota"`
+	Refresh    Duration                `yaml:"refresh"`
+	Time       Time                    `yaml:"time"`
+	Theme      string                  `yaml:"theme"`
+	Themes     map[string]styles.Theme `yaml:"themes"`
+	Colors     styles.Color            `yaml:"colors"`
+	Beams      map[string]Beam         `yaml:"beams"`
+	Highlights []Highlight             `yaml:"highlights"`
+	Keymap     string                  `yaml:"keymap"`
+	// SequenceTimeout aborts a pending key sequence (such
+	// as SPC f) if not continued in time. Zero disables it.
+	SequenceTimeout Duration          `yaml:"sequence_timeout"`
+	Keys            map[string]string `yaml:"keys"`
+	// Mouse enables scrolling, clicking and selecting with the
+	// mouse. While disabled the terminal's text selection works.
+	// Switches are pointers such that a profile can turn off
+	// what the top level turns on (see Enabled).
+	Mouse *bool `yaml:"mouse"`
+	// Index shows the index of each log in front of
+	// its line as typed into the jump and browse prompts
+	Index *bool `yaml:"index"`
+	// NoWrap cuts lines wider than the terminal instead of
+	// wrapping them; they can be scrolled horizontally
+	NoWrap *bool `yaml:"no_wrap"`
+	// ANSI decides what happens to the colors of the logs:
+	// parse (default) shows them, keep shows the logs as
+	// received and strip removes them
+	ANSI string `yaml:"ansi"`
+	// Collapse decides whether logs repeating the previous log
+	// of their beam are counted as its repeats: off (default),
+	// repeats for identical logs and templates for logs which
+	// differ only in numbers, UUIDs, hex ids or timestamps
+	Collapse string `yaml:"collapse"`
+	// Clipboard decides how logs are copied (auto, osc52 or
+	// file) and ClipboardFile where they are written to if
+	// copied to a file
+	Clipboard     string `yaml:"clipboard"`
+	ClipboardFile string `yaml:"clipboard_file"`
+	// BookmarksFile is where the bookmarks are kept
+	// in between sessions (see BookmarksPath)
+	BookmarksFile string `yaml:"bookmarks_file"`
+	// Columns are the fields shown by the table view
+	Columns []string `yaml:"columns"`
+	// TraceFields are the fields a request is followed by
+	// across beams (trace_id, request_id, correlation_id
+	// if not set)
+	TraceFields []string  `yaml:"trace_fields"`
+	Queries     []Query   `yaml:"queries"`
+	Commands    []Command `yaml:"commands"`
+}
+
+// Config is the representation of the configuration file.
+// Profiles are named Settings which are applied on top of
+// the top level Settings if selected.
+type Config struct {
+	Settings `yaml:",inline"`
+	Profiles map[string]Settings `yaml:"profiles"`
+}
+
+// Default returns the Settings scotty uses if no
+// configuration file is present.
+func Default() Settings {
+	return Settings{
+		Listeners: []Listener{
+			{Network: "unix", Addr: "/tmp/scotty.sock"},
+		},
+		Refresh: Duration(time.Millisecond * 50),
+		Theme:   styles.DarkTheme.Name,
+		Themes:  map[string]styles.Theme{},
+		Beams:   map[string]Beam{},
+		Keys:    map[string]string{},
+	}
+}
+
+const (
+	// defaultSlots is the number of logs buffered
+	// if neither buffer nor buffer_bytes is set
+	defaultSlots = 4096
+	// slotBytes is the expected average memory used by a
+	// log. If only buffer_bytes is set the number of buffered
+	// logs is derived from it.
+	slotBytes = 512
+)
+
+// Slots returns the number of logs the buffer holds. If buffer is
+// not set but buffer_bytes is, the number is derived from the byte
+// limit such that the limit rather than the number of logs decides
+// which logs are evicted. Slots are allocated as logs arrive and do
+// not take memory up front.
+func (settings Settings) Slots() int {
+	if settings.Buffer > 0 {
+		return settings.Buffer
+	}
+	if slots := int(settings.BufferSize / slotBytes); slots > defaultSlots {
+		return slots
+	}
+	return defaultSlots
+}
+
+// Load looks up the configuration file and returns the Settings
+// of the selected profile. If path is empty the file is looked up
+// in the current working directory (.scotty.yaml) and then in the home
+// directory (~/.scotty/config.yaml). If no file is found the default
+// Settings are returned. An empty profile selects the top level Settings.
+func Load(path string, profile string) (Settings, error) {
+
+	if path == "" {
+		path = lookup()
+	}
+
+	if path == "" {
+		if profile != "" {
+			return Settings{}, fmt.Errorf("profile %q requested but no config file found (looked for ./%s and ~/%s)", profile, localFile, filepath.Join(homeDir, homeFile))
+		}
+		return Default(), nil
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Settings{}, fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+
+	cfg, err := Parse(raw)
+	if err != nil {
+		return Settings{}, fmt.Errorf("invalid config file %s:\n\t%w", path, err)
+	}
+
+	settings, err := cfg.Profile(profile)
+	if err != nil {
+		return Settings{}, fmt.Errorf("invalid config file %s:\n\t%w", path, err)
+	}
+
+	return settings, nil
+}
+
+// Parse decodes the yaml and applies the parsed values
+// on top of the default Settings. Unknown keys are reported
+// as an error to catch typos early.
+func Parse(raw []byte) (*Config, error) {
+
+	cfg := Config{Settings: Default()}
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Profile merges the named profile into the top level Settings
+// and validates the result. Scalar values and lists of the profile
+// replace the top level values while maps are merged key by key.
+func (cfg Config) Profile(name string) (Settings, error) {
+
+	settings := cfg.Settings
+	if name != "" {
+		profile, ok := cfg.Profiles[name]
+		if !ok {
+			return Settings{}, fmt.Errorf("profile %q does not exist (available: %s)", name, strings.Join(cfg.profileNames(), ", "))
+		}
+		settings = settings.merge(profile)
+	}
+
+	if err := settings.Validate(); err != nil {
+		if name != "" {
+			return Settings{}, fmt.Errorf("profile %q: %w", name, err)
+		}
+		return Settings{}, err
+	}
+
+	return settings, nil
+}
+
+func (cfg Config) profileNames() []string {
+	return sortedKeys(cfg.Profiles)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Enabled reports whether the switch is set and turned
+// on. Unset switches are off.
+func Enabled(switched *bool) bool {
+	return switched != nil && *switched
+}
+
+func (settings Settings) merge(other Settings) Settings {
+
+	if len(other.Listeners) > 0 {
+		settings.Listeners = other.Listeners
+	}
+	if other.Buffer != 0 {
+		settings.Buffer = other.Buffer
+	}
+	if other.BufferSize != 0 {
+		settings.BufferSize = other.BufferSize
+	}
+	if other.BeamQuota != 0 {
+		settings.BeamQuota = other.BeamQuota
+	}
+	if other.Refresh != 0 {
+		settings.Refresh = other.Refresh
+	}
+	if len(other.Time.Fields) > 0 {
+		settings.Time.Fields = other.Time.Fields
+	}
+	if other.Time.Display != "" {
+		settings.Time.Display = other.Time.Display
+	}
+	if other.SequenceTimeout != 0 {
+		settings.SequenceTimeout = other.SequenceTimeout
+	}
+	if other.Keymap != "" {
+		settings.Keymap = other.Keymap
+	}
+	if other.Mouse != nil {
+		settings.Mouse = other.Mouse
+	}
+	if other.Index != nil {
+		settings.Index = other.Index
+	}
+	if other.NoWrap != nil {
+		settings.NoWrap = other.NoWrap
+	}
+	if other.ANSI != "" {
+		settings.ANSI = other.ANSI
+	}
+	if other.Collapse != "" {
+		settings.Collapse = other.Collapse
+	}
+	if other.Clipboard != "" {
+		settings.Clipboard = other.Clipboard
+	}
+	if other.ClipboardFile != "" {
+		settings.ClipboardFile = other.ClipboardFile
+	}
+	if other.BookmarksFile != "" {
+		settings.BookmarksFile = other.BookmarksFile
+	}
+	if len(other.Columns) > 0 {
+		settings.Columns = other.Columns
+	}
+	if len(other.TraceFields) > 0 {
+		settings.TraceFields = other.TraceFields
+	}
+	if other.Theme != "" {
+		settings.Theme = other.Theme
+	}
+	if other.Colors.Border != "" {
+		settings.Colors.Border = other.Colors.Border
+	}
+	if other.Colors.Error != "" {
+		settings.Colors.Error = other.Colors.Error
+	}
+	if other.Colors.Highlight != "" {
+		settings.Colors.Highlight = other.Colors.Highlight
+	}
+	if other.Colors.Light != "" {
+		settings.Colors.Light = other.Colors.Light
+	}
+	if len(other.Highlights) > 0 {
+		settings.Highlights = other.Highlights
+	}
+	if len(other.Queries) > 0 {
+		settings.Queries = other.Queries
+	}
+	if len(other.Commands) > 0 {
+		settings.Commands = other.Commands
+	}
+
+	settings.Beams = mergeMap(settings.Beams, other.Beams)
+	settings.Themes = mergeMap(settings.Themes, other.Themes)
+	settings.Keys = mergeMap(settings.Keys, other.Keys)
+
+	return settings
+}
+
+func mergeMap[V any](base map[string]V, other map[string]V) map[string]V {
+	merged := make(map[string]V, len(base)+len(other))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Validate checks the Settings for values scotty is not able
+// to work with. All problems found are reported at once.
+func (settings Settings) Validate() error {
+
+	var errs []string
+
+	if len(settings.Listeners) == 0 {
+		errs = append(errs, "listeners: at least one listener is required")
+	}
+	for i, ln := range settings.Listeners {
+		if ln.Network != "unix" && ln.Network != "tcp" {
+			errs = append(errs, fmt.Sprintf("listeners[%d].network: must be one of unix, tcp; got %q", i, ln.Network))
+		}
+		if ln.Addr == "" {
+			errs = append(errs, fmt.Sprintf("listeners[%d].addr: must not be empty", i))
+		}
+	}
+
+	if settings.Buffer < 0 {
+		errs = append(errs, fmt.Sprintf("buffer: must be greater than zero (or omitted); got %d", settings.Buffer))
+	}
+	if settings.BufferSize > 0 && settings.BeamQuota > settings.BufferSize {
+		errs = append(errs, fmt.Sprintf("beam_quota: must not exceed buffer_bytes (%s); got %s", settings.BufferSize, settings.BeamQuota))
+	}
+	if settings.SequenceTimeout < 0 {
+		errs = append(errs, fmt.Sprintf("sequence_timeout: must not be negative; got %s", time.Duration(settings.SequenceTimeout)))
+	}
+	if settings.Refresh < 0 {
+		errs = append(errs, fmt.Sprintf("refresh: must not be negative; got %s", time.Duration(settings.Refresh)))
+	}
+
+	switch settings.Time.Display {
+	case "", "off", "absolute", "relative":
+	default:
+		errs = append(errs, fmt.Sprintf("time.display: must be one of off, absolute, relative; got %q", settings.Time.Display))
+	}
+
+	if _, err := store.ParseANSIPolicy(settings.ANSI); err != nil {
+		errs = append(errs, fmt.Sprintf("ansi: %v", err))
+	}
+
+	if _, err := store.ParseCollapseMode(settings.Collapse); err != nil {
+		errs = append(errs, fmt.Sprintf("collapse: %v", err))
+	}
+
+	if _, err := clipboard.ParseMode(settings.Clipboard); err != nil {
+		errs = append(errs, fmt.Sprintf("clipboard: %v", err))
+	}
+
+	for _, c := range []struct{ field, color string }{
+		{"colors.border", string(settings.Colors.Border)},
+		{"colors.error", string(settings.Colors.Error)},
+		{"colors.highlight", string(settings.Colors.Highlight)},
+		{"colors.light", string(settings.Colors.Light)},
+	} {
+		if c.color != "" && !ValidColor(c.color) {
+			errs = append(errs, fmt.Sprintf("%s: %q is not a valid color (use #rrggbb or 0-255)", c.field, c.color))
+		}
+	}
+
+	if _, builtIn := styles.LookupTheme(settings.Theme); !builtIn {
+		if _, custom := settings.Themes[settings.Theme]; !custom {
+			errs = append(errs, fmt.Sprintf("theme: %q does not exist (available: %s)", settings.Theme, strings.Join(settings.themeNames(), ", ")))
+		}
+	}
+
+	for _, name := range sortedKeys(settings.Themes) {
+		theme := settings.Themes[name]
+		if theme.Extends != "" {
+			if _, builtIn := styles.LookupTheme(theme.Extends); !builtIn {
+				if _, custom := settings.Themes[theme.Extends]; !custom || theme.Extends == name {
+					errs = append(errs, fmt.Sprintf("themes.%s.extends: %q does not exist", name, theme.Extends))
+				}
+			}
+		}
+		theme.EachColor(func(field string, color *lipgloss.Color) {
+			if *color != "" && !ValidColor(string(*color)) {
+				errs = append(errs, fmt.Sprintf("themes.%s.%s: %q is not a valid color (use #rrggbb or 0-255)", name, field, *color))
+			}
+		})
+		for i, color := range append(theme.Logo, theme.Palette...) {
+			if !ValidColor(string(color)) {
+				field := fmt.Sprintf("logo[%d]", i)
+				if i >= len(theme.Logo) {
+					field = fmt.Sprintf("palette[%d]", i-len(theme.Logo))
+				}
+				errs = append(errs, fmt.Sprintf("themes.%s.%s: %q is not a valid color (use #rrggbb or 0-255)", name, field, color))
+			}
+		}
+	}
+
+	for _, label := range sortedKeys(settings.Beams) {
+		if color := settings.Beams[label].Color; color != "" && !ValidColor(color) {
+			errs = append(errs, fmt.Sprintf("beams.%s.color: %q is not a valid color (use #rrggbb or 0-255)", label, color))
+		}
+		if format := settings.Beams[label].Format; format != "" && !validFormat(format) {
+			errs = append(errs, fmt.Sprintf("beams.%s.format: %q is not a known format (use one of: %s)", label, format, strings.Join(store.PayloadFormats(), ", ")))
+		}
+		if _, err := store.ParseANSIPolicy(settings.Beams[label].ANSI); err != nil {
+			errs = append(errs, fmt.Sprintf("beams.%s.ansi: %v", label, err))
+		}
+		if _, err := store.ParseCollapseMode(settings.Beams[label].Collapse); err != nil {
+			errs = append(errs, fmt.Sprintf("beams.%s.collapse: %v", label, err))
+		}
+	}
+
+	for i, hl := range settings.Highlights {
+		if hl.Pattern == "" {
+			errs = append(errs, fmt.Sprintf("highlights[%d].pattern: must not be empty", i))
+		} else if _, err := regexp.Compile(hl.Pattern); err != nil {
+			errs = append(errs, fmt.Sprintf("highlights[%d].pattern: %v", i, err))
+		}
+		if !ValidColor(hl.Color) {
+			errs = append(errs, fmt.Sprintf("highlights[%d].color: %q is not a valid color (use #rrggbb or 0-255)", i, hl.Color))
+		}
+	}
+
+	if _, err := settings.ResolveKeymap(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	names := map[string]struct{}{}
+	for i, q := range settings.Queries {
+		if q.Name == "" {
+			errs = append(errs, fmt.Sprintf("queries[%d].name: must not be empty", i))
+		}
+		if _, ok := names[q.Name]; ok {
+			errs = append(errs, fmt.Sprintf("queries[%d].name: %q is used more than once", i, q.Name))
+		}
+		names[q.Name] = struct{}{}
+		if q.Filter == "" {
+			errs = append(errs, fmt.Sprintf("queries[%d].filter: must not be empty", i))
+		}
+		if _, err := store.ParseQuery(q.Filter); err != nil {
+			errs = append(errs, fmt.Sprintf("queries[%d].filter: %v", i, err))
+		}
+	}
+
+	for i, cmd := range settings.Commands {
+		if strings.TrimSpace(cmd.Run) == "" {
+			errs = append(errs, fmt.Sprintf("commands[%d].run: must not be empty", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ResolveKeymap returns the keymap preset with
+// the keys of the Settings applied on top
+func (settings Settings) ResolveKeymap() (bindings.Keymap, error) {
+	return bindings.Resolve(settings.Keymap, settings.Keys)
+}
+
+// themeNames returns the names of the built-in
+// and custom themes in alphabetical order
+func (settings Settings) themeNames() []string {
+	names := styles.ThemeNames()
+	for _, name := range sortedKeys(settings.Themes) {
+		if _, builtIn := styles.LookupTheme(name); !builtIn {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme registers the custom themes and returns the
+// selected theme with the colors section applied on top.
+func (settings Settings) LoadTheme() (styles.Theme, error) {
+
+	// a custom theme can extend another custom theme
+	// which must be registered first
+	var register func(name string, seen map[string]bool) error
+	register = func(name string, seen map[string]bool) error {
+		theme, ok := settings.Themes[name]
+		if !ok {
+			return nil
+		}
+		if seen[name] {
+			return fmt.Errorf("themes.%s: extends itself", name)
+		}
+		seen[name] = true
+
+		if err := register(theme.Extends, seen); err != nil {
+			return err
+		}
+		theme.Name = name
+		return styles.Register(theme)
+	}
+
+	for _, name := range sortedKeys(settings.Themes) {
+		if err := register(name, map[string]bool{}); err != nil {
+			return styles.Theme{}, err
+		}
+	}
+
+	theme, ok := styles.LookupTheme(settings.Theme)
+	if !ok {
+		return styles.Theme{}, fmt.Errorf("theme: %q does not exist (available: %s)", settings.Theme, strings.Join(styles.ThemeNames(), ", "))
+	}
+
+	if settings.Colors.Border != "" {
+		theme.Border = settings.Colors.Border
+	}
+	if settings.Colors.Error != "" {
+		theme.Error = settings.Colors.Error
+	}
+	if settings.Colors.Highlight != "" {
+		theme.Highlight = settings.Colors.Highlight
+	}
+	if settings.Colors.Light != "" {
+		theme.Light = settings.Colors.Light
+	}
+
+	return theme, nil
+}
+
+// ValidColor reports whether the color can be used with lipgloss.
+// Valid are hex colors (#fff, #ffffff) and ANSI colors (0-255).
+func ValidColor(color string) bool {
+	if hexColor.MatchString(color) {
+		return true
+	}
+
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// BookmarksPath returns the file the bookmarks are kept in
+// between sessions: the BookmarksFile if set or else a file
+// in the home directory. It is empty if the home directory
+// is unknown.
+func (settings Settings) BookmarksPath() string {
+	if settings.BookmarksFile != "" {
+		return settings.BookmarksFile
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, homeDir, bookmarksFile)
+}
+
+func lookup() string {
+	if _, err := os.Stat(localFile); err == nil {
+		return localFile
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	path := filepath.Join(home, homeDir, homeFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+func validFormat(name string) bool {
+	for _, format := range store.PayloadFormats() {
+		if format == name {
+			return true
+		}
+	}
+	return false
+}
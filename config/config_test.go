@@ -0,0 +1,229 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+buffer: 2048
+refresh: 100ms
+mouse: true
+index: true
+beams:
+  checkout-svc:
+    color: "#ff4c94"
+highlights:
+  - pattern: '"level":"error"'
+    color: "196"
+queries:
+  - name: errors
+    filter: level=error
+profiles:
+  checkout-flow:
+    buffer: 8192
+    mouse: false
+    listeners:
+      - network: tcp
+        addr: ":50000"
+    beams:
+      payment-svc:
+        color: "43"
+    commands:
+      - name: payment
+        run: go run ./cmd/payment 2>&1 | beam payment-svc
+`
+
+func TestParseProfile(t *testing.T) {
+
+	cfg, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("unable to parse config: %v", err)
+	}
+
+	base, err := cfg.Profile("")
+	if err != nil {
+		t.Fatalf("unable to resolve top level settings: %v", err)
+	}
+
+	if base.Buffer != 2048 {
+		t.Fatalf("[base] wanted buffer: %d - got: %d", 2048, base.Buffer)
+	}
+	if time.Duration(base.Refresh) != time.Millisecond*100 {
+		t.Fatalf("[base] wanted refresh: %s - got: %s", time.Millisecond*100, time.Duration(base.Refresh))
+	}
+	if !Enabled(base.Mouse) {
+		t.Fatalf("[base] wanted mouse to be enabled")
+	}
+	if len(base.Listeners) != 1 || base.Listeners[0].Network != "unix" {
+		t.Fatalf("[base] wanted default listener - got: %+v", base.Listeners)
+	}
+
+	profile, err := cfg.Profile("checkout-flow")
+	if err != nil {
+		t.Fatalf("unable to resolve profile: %v", err)
+	}
+
+	if profile.Buffer != 8192 {
+		t.Fatalf("[profile] wanted buffer: %d - got: %d", 8192, profile.Buffer)
+	}
+	if time.Duration(profile.Refresh) != time.Millisecond*100 {
+		t.Fatalf("[profile] wanted refresh from top level: %s - got: %s", time.Millisecond*100, time.Duration(profile.Refresh))
+	}
+	if Enabled(profile.Mouse) {
+		t.Fatalf("[profile] wanted mouse to be turned off by the profile")
+	}
+	if !Enabled(profile.Index) {
+		t.Fatalf("[profile] wanted index from top level")
+	}
+	if len(profile.Listeners) != 1 || profile.Listeners[0].Network != "tcp" {
+		t.Fatalf("[profile] wanted tcp listener - got: %+v", profile.Listeners)
+	}
+	if len(profile.Beams) != 2 {
+		t.Fatalf("[profile] wanted beams to be merged - got: %+v", profile.Beams)
+	}
+	if len(profile.Commands) != 1 {
+		t.Fatalf("[profile] wanted 1 command - got: %d", len(profile.Commands))
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+
+	tt := []struct {
+		name    string
+		raw     string
+		profile string
+		want    []string
+	}{
+		{
+			name: "unknown key",
+			raw:  "bufer: 12",
+			want: []string{"field bufer not found"},
+		},
+		{
+			name: "malformed duration",
+			raw:  "refresh: fast",
+			want: []string{`invalid duration "fast"`},
+		},
+		{
+			name: "invalid values",
+			raw: `
+buffer: -1
+collapse: always
+listeners:
+  - network: udp
+    addr: ""
+highlights:
+  - pattern: "(unclosed"
+    color: blue
+beams:
+  api:
+    format: csv
+    ansi: colorful
+`,
+			want: []string{
+				"buffer: must be greater than zero",
+				`collapse: unknown collapse mode "always"`,
+				"listeners[0].network: must be one of unix, tcp",
+				"listeners[0].addr: must not be empty",
+				"highlights[0].pattern:",
+				`highlights[0].color: "blue" is not a valid color`,
+				`beams.api.format: "csv" is not a known format`,
+				`beams.api.ansi: unknown ansi policy "colorful"`,
+			},
+		},
+		{
+			name:    "unknown profile",
+			raw:     "profiles:\n  dev:\n    buffer: 12",
+			profile: "prod",
+			want:    []string{`profile "prod" does not exist (available: dev)`},
+		},
+	}
+
+	for _, tc := range tt {
+		cfg, err := Parse([]byte(tc.raw))
+		if err == nil {
+			_, err = cfg.Profile(tc.profile)
+		}
+
+		if err == nil {
+			t.Fatalf("[%s] wanted error - got nil", tc.name)
+		}
+
+		for _, want := range tc.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("[%s] wanted error to contain: %q - got: %q", tc.name, want, err.Error())
+			}
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+
+	tt := []struct {
+		raw  string
+		want ByteSize
+	}{
+		{raw: "4096", want: 4096},
+		{raw: "512KB", want: 512 << 10},
+		{raw: "256MB", want: 256 << 20},
+		{raw: "1.5gb", want: 3 << 29},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseByteSize(tc.raw)
+		if err != nil {
+			t.Fatalf("[%s] unable to parse: %v", tc.raw, err)
+		}
+		if got != tc.want {
+			t.Fatalf("[%s] wanted: %d - got: %d", tc.raw, tc.want, got)
+		}
+	}
+
+	if _, err := ParseByteSize("lots"); err == nil {
+		t.Fatalf("wanted error for invalid size - got nil")
+	}
+}
+
+func TestLoadCustomTheme(t *testing.T) {
+
+	raw := `
+theme: paper
+colors:
+  error: "196"
+themes:
+  paper:
+    extends: light
+    footer: "#fdf6e3"
+  broken:
+    extends: paper
+    tab: blue
+`
+	cfg, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("unable to parse config: %v", err)
+	}
+
+	if _, err := cfg.Profile(""); err == nil || !strings.Contains(err.Error(), `themes.broken.tab: "blue" is not a valid color`) {
+		t.Fatalf("wanted error for invalid theme color - got: %v", err)
+	}
+
+	delete(cfg.Themes, "broken")
+	settings, err := cfg.Profile("")
+	if err != nil {
+		t.Fatalf("unable to resolve settings: %v", err)
+	}
+
+	theme, err := settings.LoadTheme()
+	if err != nil {
+		t.Fatalf("unable to load theme: %v", err)
+	}
+
+	if theme.Footer != "#fdf6e3" || theme.Error != "196" {
+		t.Fatalf("wanted footer and error to be overwritten - got: %s, %s", theme.Footer, theme.Error)
+	}
+	if theme.Tab == "" {
+		t.Fatalf("wanted tab color from base theme - got none")
+	}
+}
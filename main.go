@@ -1,12 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"time"
 
 	"github.com/KonstantinGasser/scotty/app"
+	"github.com/KonstantinGasser/scotty/app/bindings"
+	"github.com/KonstantinGasser/scotty/app/clipboard"
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/config"
 	"github.com/KonstantinGasser/scotty/store"
 	"github.com/KonstantinGasser/scotty/stream"
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,15 +30,88 @@ func main() {
 		return
 	}
 
+	configPath := flag.String("config", "", "path to the config file (default: ./.scotty.yaml or ~/.scotty/config.yaml)")
+	profile := flag.String("profile", "", "name of the config profile to apply")
 	network := flag.String("network", "unix", "network interface to listen for beams (option: tcp)")
 	addr := flag.String("addr", "/tmp/scotty.sock", "address for the network interface")
-	buffer := flag.Int("buffer", 4096, "buffer to store logs will hold up N items")
+	buffer := flag.Int("buffer", 0, "buffer to store logs will hold up N items (default: 4096 or derived from -buffer-bytes)")
+	var bufferBytes, beamQuota config.ByteSize
+	flag.Var(&bufferBytes, "buffer-bytes", "memory the buffered logs may use before the oldest are evicted (e.g. 256MB)")
+	flag.Var(&beamQuota, "beam-quota", "memory the logs of a single beam may use before its oldest are evicted (e.g. 32MB)")
+	keymap := flag.String("keymap", "", "key binding preset (options: default, vim, emacs)")
+	theme := flag.String("theme", "", "color theme of the UI (built-in: dark, light, high-contrast)")
+	mouse := flag.Bool("mouse", false, "enable mouse support (disables the terminal's text selection)")
 	refresh := flag.Duration("refresh", time.Millisecond*50, "refresh rate of the pager. Can be increased if high through put is expected in order to reduce lags")
 	flag.Parse()
 
+	cfg, err := config.Load(*configPath, *profile)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	// -network and -addr overwrite the field they set
+	// of the first listener from the config file
+	listener := config.Listener{Network: *network, Addr: *addr}
+	if len(cfg.Listeners) > 0 {
+		listener = cfg.Listeners[0]
+	}
+
+	// flags explicitly set on the command line
+	// overwrite the values from the config file
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "network":
+			listener.Network = *network
+			cfg.Listeners = []config.Listener{listener}
+		case "addr":
+			listener.Addr = *addr
+			cfg.Listeners = []config.Listener{listener}
+		case "buffer":
+			cfg.Buffer = *buffer
+		case "buffer-bytes":
+			cfg.BufferSize = bufferBytes
+		case "beam-quota":
+			cfg.BeamQuota = beamQuota
+		case "keymap":
+			cfg.Keymap = *keymap
+		case "theme":
+			cfg.Theme = *theme
+		case "mouse":
+			cfg.Mouse = mouse
+		case "refresh":
+			cfg.Refresh = config.Duration(*refresh)
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("invalid flags:\n\t%v\n", err)
+		return
+	}
+
+	uiTheme, err := cfg.LoadTheme()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	styles.SetTheme(uiTheme)
+
+	// the mode is validated by cfg.Validate
+	copyMode, _ := clipboard.ParseMode(cfg.Clipboard)
+	clipboard.Configure(copyMode, cfg.ClipboardFile)
+
+	// conflicts are reported by cfg.Validate
+	keys, _ := cfg.ResolveKeymap()
+	bindings.Use(keys)
+
 	quite := make(chan struct{})
 
-	multiplex, err := stream.New(quite, *network, *addr)
+	var addrs []stream.Address
+	for _, ln := range cfg.Listeners {
+		addrs = append(addrs, stream.Address{Network: ln.Network, Addr: ln.Addr})
+	}
+
+	multiplex, err := stream.New(quite, addrs...)
 	if err != nil {
 		fmt.Println(err.Error())
 		return
@@ -37,15 +119,139 @@ func main() {
 
 	go multiplex.Run()
 
-	lStore := store.New(uint32(*buffer))
-	ui := app.New(quite, *refresh, lStore, multiplex)
+	cmds, err := startCommands(cfg.Commands)
+	defer stopCommands(cmds)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
 
-	bubble := tea.NewProgram(ui,
-		tea.WithAltScreen(),
-	)
+	lStore := store.New(uint32(cfg.Slots()))
+	lStore.LimitBytes(uint64(cfg.BufferSize))
+	lStore.Quota("", uint64(cfg.BeamQuota))
+	// policies are validated by cfg.Validate
+	ansiPolicy, _ := store.ParseANSIPolicy(cfg.ANSI)
+	lStore.SetANSIPolicy("", ansiPolicy)
+	collapseMode, _ := store.ParseCollapseMode(cfg.Collapse)
+	lStore.SetCollapseMode("", collapseMode)
+	for label, beam := range cfg.Beams {
+		if beam.Quota > 0 {
+			lStore.Quota(label, uint64(beam.Quota))
+		}
+		if beam.ANSI != "" {
+			policy, _ := store.ParseANSIPolicy(beam.ANSI)
+			lStore.SetANSIPolicy(label, policy)
+		}
+		if beam.Collapse != "" {
+			mode, _ := store.ParseCollapseMode(beam.Collapse)
+			lStore.SetCollapseMode(label, mode)
+		}
+	}
+	lStore.PreferLogTime(cfg.Time.Fields...)
+	// display is validated while loading the config
+	timeMode, _ := store.ParseTimeMode(cfg.Time.Display)
+	lStore.SetTimeMode(timeMode)
+	lStore.ShowIndex(config.Enabled(cfg.Index))
+	lStore.WrapLines(!config.Enabled(cfg.NoWrap))
 
-	if _, err := bubble.Run(); err != nil {
-		fmt.Printf("unable to start scotty: %v", err)
+	bookmarksPath := cfg.BookmarksPath()
+	if err := restoreBookmarks(lStore, bookmarksPath); err != nil {
+		fmt.Println(err.Error())
 		return
 	}
+
+	ui := app.New(quite, cfg, lStore, multiplex)
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if config.Enabled(cfg.Mouse) {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+
+	bubble := tea.NewProgram(ui, opts...)
+
+	_, runErr := bubble.Run()
+	if err := saveBookmarks(lStore, bookmarksPath); err != nil {
+		fmt.Println(err.Error())
+	}
+	if runErr != nil {
+		fmt.Printf("unable to start scotty: %v", runErr)
+		return
+	}
+}
+
+// restoreBookmarks restores the bookmarks of previous
+// sessions from the file if it exists
+func restoreBookmarks(lStore *store.Store, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to restore bookmarks: %w", err)
+	}
+	defer f.Close()
+
+	if err := lStore.RestoreBookmarks(f); err != nil {
+		return fmt.Errorf("%w (remove %s to start without them)", err, path)
+	}
+	return nil
+}
+
+// saveBookmarks keeps the bookmarks for the next session.
+// Without bookmarks no file is created.
+func saveBookmarks(lStore *store.Store, path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && len(lStore.Bookmarks()) == 0 {
+		return nil
+	}
+
+	var saved bytes.Buffer
+	if err := lStore.SaveBookmarks(&saved); err != nil {
+		return fmt.Errorf("unable to save bookmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to save bookmarks: %w", err)
+	}
+	if err := os.WriteFile(path, saved.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("unable to save bookmarks: %w", err)
+	}
+	return nil
+}
+
+// startCommands runs the auto-start commands of the config
+// in the background. Output of the commands is discarded as
+// it would otherwise end up in the UI - commands are expected
+// to pipe their output into beam.
+func startCommands(commands []config.Command) ([]*exec.Cmd, error) {
+
+	var started []*exec.Cmd
+	for _, c := range commands {
+		cmd := exec.Command("sh", "-c", c.Run)
+		// own process group (where supported) so that the entire pipe
+		// chain can be stopped once scotty exits
+		detach(cmd)
+
+		if err := cmd.Start(); err != nil {
+			return started, fmt.Errorf("unable to start command %q: %w", c.Name, err)
+		}
+		started = append(started, cmd)
+	}
+
+	return started, nil
+}
+
+func stopCommands(cmds []*exec.Cmd) {
+	for _, cmd := range cmds {
+		if cmd.Process == nil {
+			continue
+		}
+		terminate(cmd)
+		cmd.Wait()
+	}
 }
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ANSIPolicy decides what happens to the ANSI escape sequences
+// (colors) of a beam's logs. Whatever the policy, queries, search
+// and copying operate on the logs without escape sequences.
+type ANSIPolicy int
+
+const (
+	// ANSIParse shows the colors and text attributes of
+	// the logs and drops any other escape sequence
+	ANSIParse ANSIPolicy = iota
+	// ANSIKeep shows the logs with their escape
+	// sequences as received
+	ANSIKeep
+	// ANSIStrip shows the logs without colors
+	ANSIStrip
+)
+
+// ANSIPolicies are the names of the policies
+// as used in the config
+var ANSIPolicies = []string{"parse", "keep", "strip"}
+
+// ParseANSIPolicy maps the name of an ANSIPolicy
+// as used in the config to its ANSIPolicy
+func ParseANSIPolicy(name string) (ANSIPolicy, error) {
+	switch name {
+	case "", "parse":
+		return ANSIParse, nil
+	case "keep":
+		return ANSIKeep, nil
+	case "strip":
+		return ANSIStrip, nil
+	}
+	return ANSIParse, fmt.Errorf("unknown ansi policy %q (options: %s)", name, strings.Join(ANSIPolicies, ", "))
+}
+
+// ansiPolicies maps beams to their ANSIPolicy
+type ansiPolicies struct {
+	labels map[string]ANSIPolicy
+	// fallback applies to beams
+	// without a policy of their own
+	fallback ANSIPolicy
+}
+
+// split returns the data without escape sequences and the
+// data as shown according to the policy of the beam. The
+// shown data is empty if it is the same as the plain data.
+func (policies ansiPolicies) split(label string, data string) (string, string) {
+	if strings.IndexByte(data, escape) < 0 {
+		return data, ""
+	}
+
+	policy, ok := policies.labels[label]
+	if !ok {
+		policy = policies.fallback
+	}
+
+	plain := stripANSI(data)
+	switch policy {
+	case ANSIKeep:
+		return plain, data
+	case ANSIParse:
+		return plain, renderSpans(parseSpans(data))
+	}
+	return plain, ""
+}
+
+// stripANSI removes all escape sequences of s
+func stripANSI(s string) string {
+	var plain strings.Builder
+	plain.Grow(len(s))
+	for len(s) > 0 {
+		i := strings.IndexByte(s, escape)
+		if i < 0 {
+			plain.WriteString(s)
+			break
+		}
+		plain.WriteString(s[:i])
+		s = s[i+escapeLen(s[i:]):]
+	}
+	return plain.String()
+}
+
+// span is a part of a log shown in the same style
+type span struct {
+	sgr  sgrAttributes
+	text string
+}
+
+// parseSpans breaks s into spans of the styles set by its
+// SGR sequences. Other escape sequences are dropped.
+func parseSpans(s string) []span {
+	var spans []span
+	var sgr sgrAttributes
+	for len(s) > 0 {
+		i := strings.IndexByte(s, escape)
+		if i < 0 {
+			i = len(s)
+		}
+		if i > 0 {
+			spans = append(spans, span{sgr: sgr, text: s[:i]})
+			s = s[i:]
+			continue
+		}
+
+		n := escapeLen(s)
+		if seq := s[:n]; len(seq) > 2 && seq[1] == '[' && seq[n-1] == 'm' {
+			sgr.apply(seq[2 : n-1])
+		}
+		s = s[n:]
+	}
+	return spans
+}
+
+// renderSpans renders each span in its style. Lines are
+// rendered one by one such that they are not aligned.
+func renderSpans(spans []span) string {
+	var rendered strings.Builder
+	for _, sp := range spans {
+		style := sp.sgr.style()
+		for i, line := range strings.Split(sp.text, "\n") {
+			if i > 0 {
+				rendered.WriteByte('\n')
+			}
+			if line != "" {
+				rendered.WriteString(style.Render(line))
+			}
+		}
+	}
+	return rendered.String()
+}
+
+// sgrAttributes are the colors and text attributes
+// set by the SGR sequences read so far
+type sgrAttributes struct {
+	fg, bg                                   string
+	bold, faint, italic, underline, reversed bool
+}
+
+// apply updates the attributes with the parameters
+// of an SGR sequence (such as "1;38;5;208")
+func (sgr *sgrAttributes) apply(params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil && codes[i] != "" {
+			continue
+		}
+
+		switch {
+		case code == 0:
+			*sgr = sgrAttributes{}
+		case code == 1:
+			sgr.bold = true
+		case code == 2:
+			sgr.faint = true
+		case code == 3:
+			sgr.italic = true
+		case code == 4:
+			sgr.underline = true
+		case code == 7:
+			sgr.reversed = true
+		case code == 22:
+			sgr.bold, sgr.faint = false, false
+		case code == 23:
+			sgr.italic = false
+		case code == 24:
+			sgr.underline = false
+		case code == 27:
+			sgr.reversed = false
+		case code >= 30 && code <= 37:
+			sgr.fg = strconv.Itoa(code - 30)
+		case code >= 90 && code <= 97:
+			sgr.fg = strconv.Itoa(code - 90 + 8)
+		case code == 39:
+			sgr.fg = ""
+		case code >= 40 && code <= 47:
+			sgr.bg = strconv.Itoa(code - 40)
+		case code >= 100 && code <= 107:
+			sgr.bg = strconv.Itoa(code - 100 + 8)
+		case code == 49:
+			sgr.bg = ""
+		case code == 38 || code == 48:
+			color, n := extendedColor(codes[i+1:])
+			i += n
+			if code == 38 {
+				sgr.fg = color
+			} else {
+				sgr.bg = color
+			}
+		}
+	}
+}
+
+// extendedColor reads the color of a 38 or 48 code from the
+// following parameters (5;n or 2;r;g;b) and returns the color
+// and the number of parameters read
+func extendedColor(params []string) (string, int) {
+	if len(params) >= 2 && params[0] == "5" {
+		return params[1], 2
+	}
+	if len(params) >= 4 && params[0] == "2" {
+		var rgb [3]int
+		for i := range rgb {
+			rgb[i], _ = strconv.Atoi(params[1+i])
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0]&0xff, rgb[1]&0xff, rgb[2]&0xff), 4
+	}
+	return "", len(params)
+}
+
+// style returns the lipgloss style of the attributes
+func (sgr sgrAttributes) style() lipgloss.Style {
+	style := lipgloss.NewStyle().
+		Bold(sgr.bold).
+		Faint(sgr.faint).
+		Italic(sgr.italic).
+		Underline(sgr.underline).
+		Reverse(sgr.reversed)
+	if sgr.fg != "" {
+		style = style.Foreground(lipgloss.Color(sgr.fg))
+	}
+	if sgr.bg != "" {
+		style = style.Background(lipgloss.Color(sgr.bg))
+	}
+	return style
+}
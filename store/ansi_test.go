@@ -0,0 +1,78 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestANSIPolicy(t *testing.T) {
+
+	// zap's console encoder colors the level
+	colored := "\x1b[2K\x1b[31mERROR\x1b[0m\tlevel=error msg=\x1b[1mboom\x1b[0m"
+	plain := "ERROR\tlevel=error msg=boom"
+
+	store := New(8)
+	store.SetANSIPolicy("", ANSIKeep)
+	store.SetANSIPolicy("api", ANSIStrip)
+	store.SetANSIPolicy("db", ANSIParse)
+
+	for _, label := range []string{"web", "api", "db"} {
+		store.Insert(label, time.Now(), []byte(colored))
+	}
+	store.Insert("web", time.Now(), []byte("\x1b[0m"))
+	if _, latest, _ := store.buffer.Window(); latest != 2 {
+		t.Fatalf("wanted logs only made of escape sequences to be dropped")
+	}
+
+	query, err := ParseQuery("level=error msg=boom")
+	if err != nil {
+		t.Fatalf("unable to parse query: %v", err)
+	}
+	for offset := uint32(0); offset < 3; offset++ {
+		item := store.buffer.At(offset)
+		if item.Raw != plain {
+			t.Fatalf("[%s] wanted the log without escape sequences, got: %q", item.Label, item.Raw)
+		}
+		if !query.Match(item.Raw) {
+			t.Fatalf("[%s] wanted the query to match the log", item.Label)
+		}
+	}
+
+	if keep := store.buffer.At(0); keep.Display() != colored {
+		t.Fatalf("[keep] wanted the log as received, got: %q", keep.Display())
+	}
+	if strip := store.buffer.At(1); strip.Display() != plain {
+		t.Fatalf("[strip] wanted the log without colors, got: %q", strip.Display())
+	}
+	parse := store.buffer.At(2).Display()
+	if strings.Contains(parse, "\x1b[2K") {
+		t.Fatalf("[parse] wanted escape sequences other than colors to be dropped, got: %q", parse)
+	}
+	if got := printable(parse); got != printable(plain) {
+		t.Fatalf("[parse] wanted the text of the log, got: %q", got)
+	}
+}
+
+func TestParseSpans(t *testing.T) {
+
+	spans := parseSpans("\x1b[1;38;5;208mhot\x1b[22m warm\x1b[0m \x1b[48;2;0;0;255mcold\x1b[m")
+
+	want := []struct {
+		text string
+		sgr  sgrAttributes
+	}{
+		{text: "hot", sgr: sgrAttributes{fg: "208", bold: true}},
+		{text: " warm", sgr: sgrAttributes{fg: "208"}},
+		{text: " "},
+		{text: "cold", sgr: sgrAttributes{bg: "#0000ff"}},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("wanted %d spans, got: %d", len(want), len(spans))
+	}
+	for i, sp := range spans {
+		if sp.text != want[i].text || sp.sgr != want[i].sgr {
+			t.Fatalf("span %d: wanted %q in %+v, got: %q in %+v", i, want[i].text, want[i].sgr, sp.text, sp.sgr)
+		}
+	}
+}
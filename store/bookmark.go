@@ -0,0 +1,270 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/KonstantinGasser/scotty/store/ring"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// bookmarkDivider replaces the divider of
+	// bookmarked logs (same width as the divider)
+	bookmarkDivider = " ◆ "
+)
+
+// Bookmark marks a log to come back to it later
+type Bookmark struct {
+	// Offset of the log in the buffer
+	Offset uint32
+	Label  string
+	// Time of the log (see ring.Item.Time)
+	Time time.Time
+	// Note is an optional note attached to the log
+	Note string
+	// Raw is the log as bookmarked which is known
+	// even once the buffer dropped the log
+	Raw string
+	// Overwritten is true once the buffer overwrote or
+	// evicted the log. It is set by Store.Bookmarks.
+	Overwritten bool
+	// Restored is true if the bookmark was made in a previous
+	// session (see Store.RestoreBookmarks). Its offset refers
+	// to the buffer of that session.
+	Restored bool
+}
+
+// savedBookmark is a bookmark as written
+// by Store.SaveBookmarks
+type savedBookmark struct {
+	Offset uint32    `json:"offset"`
+	Label  string    `json:"label"`
+	Time   time.Time `json:"time"`
+	Note   string    `json:"note,omitempty"`
+	Raw    string    `json:"raw"`
+}
+
+// bookmarks by offset shared by the store
+// with the prefixer marking bookmarked logs
+type bookmarks map[uint32]*Bookmark
+
+// toggle bookmarks the log at the offset or removes the
+// bookmark if the log is already bookmarked. It reports
+// whether the log is bookmarked afterwards.
+func (b bookmarks) toggle(reader ring.Reader, offset uint32) (bool, error) {
+	if _, ok := b[offset]; ok {
+		delete(b, offset)
+		return false, nil
+	}
+
+	item := reader.At(offset)
+	if item.Index() != offset+1 || item.Evicted {
+		return false, fmt.Errorf("log %d is no longer buffered", offset)
+	}
+	b[offset] = &Bookmark{
+		Offset: offset,
+		Label:  item.Label,
+		Time:   item.Time(),
+		Raw:    item.Raw,
+	}
+	return true, nil
+}
+
+// annotate attaches the note to the bookmark of the log
+func (b bookmarks) annotate(offset uint32, note string) error {
+	bookmark, ok := b[offset]
+	if !ok {
+		return fmt.Errorf("log %d is not bookmarked", offset)
+	}
+	bookmark.Note = note
+	return nil
+}
+
+// RemoveBookmark removes the bookmark which
+// may be restored from a previous session
+func (store *Store) RemoveBookmark(bookmark Bookmark) {
+	if !bookmark.Restored {
+		delete(store.bookmarks, bookmark.Offset)
+		return
+	}
+	for i, restored := range store.restored {
+		if restored.Offset == bookmark.Offset && restored.Time.Equal(bookmark.Time) {
+			store.restored = append(store.restored[:i], store.restored[i+1:]...)
+			return
+		}
+	}
+}
+
+// ToggleBookmark bookmarks the log at the offset or removes
+// the bookmark if the log is already bookmarked. It reports
+// whether the log is bookmarked afterwards.
+func (store *Store) ToggleBookmark(offset uint32) (bool, error) {
+	return store.bookmarks.toggle(store.buffer, offset)
+}
+
+// Annotate attaches the note to the bookmark of the log
+func (store *Store) Annotate(offset uint32, note string) error {
+	return store.bookmarks.annotate(offset, note)
+}
+
+// ToggleBookmark bookmarks the log at the offset or
+// removes its bookmark. The page shows the change.
+func (pager *Pager) ToggleBookmark(offset uint32) (bool, error) {
+	marked, err := pager.bookmarks.toggle(pager.reader, offset)
+	if err == nil {
+		pager.Rebuild()
+		pager.Refresh()
+	}
+	return marked, err
+}
+
+// Annotate attaches the note to the bookmark of the log
+func (pager *Pager) Annotate(offset uint32, note string) error {
+	return pager.bookmarks.annotate(offset, note)
+}
+
+// ToggleBookmark bookmarks the selected log
+// or removes its bookmark
+func (formatter *Formatter) ToggleBookmark() (bool, error) {
+	marked, err := formatter.bookmarks.toggle(formatter.reader, formatter.absolute)
+	if err == nil {
+		formatter.buildView()
+	}
+	return marked, err
+}
+
+// Annotate attaches the note to the bookmark of the
+// selected log which is shown next to its prefix
+func (formatter *Formatter) Annotate(note string) error {
+	if err := formatter.bookmarks.annotate(formatter.absolute, note); err != nil {
+		return err
+	}
+	formatter.buildView()
+	return nil
+}
+
+// Bookmarks returns the bookmarks restored from previous
+// sessions followed by all bookmarks of this session ordered
+// by offset flagging the ones the buffer no longer holds
+func (store *Store) Bookmarks() []Bookmark {
+	list := make([]Bookmark, 0, len(store.restored)+len(store.bookmarks))
+	list = append(list, store.restored...)
+
+	current := make([]Bookmark, 0, len(store.bookmarks))
+	for offset, bookmark := range store.bookmarks {
+		b := *bookmark
+		item := store.buffer.At(offset)
+		b.Overwritten = item.Index() != offset+1 || item.Evicted
+		current = append(current, b)
+	}
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].Offset < current[j].Offset
+	})
+	return append(list, current...)
+}
+
+// SaveBookmarks writes all bookmarks as JSON
+// such that the next session can restore them
+func (store *Store) SaveBookmarks(w io.Writer) error {
+	bookmarks := store.Bookmarks()
+	saved := make([]savedBookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		saved = append(saved, savedBookmark{
+			Offset: b.Offset,
+			Label:  b.Label,
+			Time:   b.Time,
+			Note:   b.Note,
+			Raw:    b.Raw,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(saved)
+}
+
+// RestoreBookmarks reads the bookmarks written by SaveBookmarks.
+// Their logs are not part of the buffer and can no longer be
+// browsed; the bookmarks are listed with their log and note
+// until removed.
+func (store *Store) RestoreBookmarks(r io.Reader) error {
+	var saved []savedBookmark
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return fmt.Errorf("unable to read bookmarks: %w", err)
+	}
+
+	for _, b := range saved {
+		store.restored = append(store.restored, Bookmark{
+			Offset:      b.Offset,
+			Label:       b.Label,
+			Time:        b.Time,
+			Note:        b.Note,
+			Raw:         b.Raw,
+			Overwritten: true,
+			Restored:    true,
+		})
+	}
+	return nil
+}
+
+// renderCopied returns the line copied in front of
+// a bookmarked log holding the note of the bookmark
+func renderCopied(bookmark *Bookmark) string {
+	if bookmark.Note == "" {
+		return "# bookmark"
+	}
+	return "# bookmark: " + bookmark.Note
+}
+
+// NextBookmark selects the first bookmarked log still held by
+// the buffer after the selected log (before if delta is negative)
+func (formatter *Formatter) NextBookmark(delta int) error {
+	var next *uint32
+	for offset := range formatter.bookmarks {
+		offset := offset
+		item := formatter.reader.At(offset)
+		if item.Index() != offset+1 || item.Evicted {
+			continue
+		}
+		after := delta > 0 && offset > formatter.absolute && (next == nil || offset < *next)
+		before := delta < 0 && offset < formatter.absolute && (next == nil || offset > *next)
+		if after || before {
+			next = &offset
+		}
+	}
+	if next == nil {
+		return fmt.Errorf("no bookmark further")
+	}
+
+	formatter.Move(int(*next) - int(formatter.absolute))
+	return nil
+}
+
+// bookmarked reports whether the item is bookmarked
+func (b bookmarks) bookmarked(offset uint32) bool {
+	_, ok := b[offset]
+	return ok
+}
+
+// renderNote renders the note of a bookmark
+// next to the prefix in the browse view
+func renderNote(note string) string {
+	return lipgloss.NewStyle().
+		Italic(true).
+		Foreground(styles.Current().Highlight).
+		Render(note)
+}
+
+// renderBookmarkDivider renders the divider of
+// a bookmarked log in the highlight color
+func renderBookmarkDivider() string {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(styles.Current().Highlight).
+		Render(bookmarkDivider)
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBookmarks(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(12, 60)
+
+	for _, data := range []string{"zero", "one", "two"} {
+		store.Insert("api", time.Now(), []byte(data))
+	}
+
+	for _, offset := range []uint32{0, 2} {
+		if marked, err := store.ToggleBookmark(offset); err != nil || !marked {
+			t.Fatalf("unable to bookmark log %d: %v", offset, err)
+		}
+	}
+	if err := store.Annotate(2, "retry starts here"); err != nil {
+		t.Fatalf("unable to annotate the bookmark: %v", err)
+	}
+	if err := store.Annotate(1, "not bookmarked"); err == nil {
+		t.Fatalf("wanted an error annotating a log without bookmark")
+	}
+
+	formatter.Load(1)
+	if lines := strings.Split(formatter.background, "\n"); !strings.Contains(lines[1], "◆") || strings.Contains(lines[0], "◆") {
+		t.Fatalf("wanted the bookmark in place of the divider, got:\n%s", formatter.background)
+	}
+	if err := formatter.NextBookmark(1); err != nil || formatter.CurrentIndex() != 2 {
+		t.Fatalf("wanted log 2 to be selected, got: %d (%v)", formatter.CurrentIndex(), err)
+	}
+	if err := formatter.NextBookmark(1); err == nil {
+		t.Fatalf("wanted an error without a bookmark further")
+	}
+
+	// the buffer overwrites the first log
+	for _, data := range []string{"three", "four"} {
+		store.Insert("api", time.Now(), []byte(data))
+	}
+
+	list := store.Bookmarks()
+	if len(list) != 2 {
+		t.Fatalf("wanted 2 bookmarks, got: %d", len(list))
+	}
+	if !list[0].Overwritten || list[0].Raw != "zero" {
+		t.Fatalf("wanted bookmark 0 to be overwritten keeping the log, got: %+v", list[0])
+	}
+	if list[1].Overwritten || list[1].Note != "retry starts here" {
+		t.Fatalf("wanted bookmark 2 with its note, got: %+v", list[1])
+	}
+
+	// overwritten logs are skipped
+	if err := formatter.NextBookmark(-1); err == nil {
+		t.Fatalf("wanted an error jumping to an overwritten log")
+	}
+	if _, err := store.ToggleBookmark(4); err != nil {
+		t.Fatalf("unable to bookmark log 4: %v", err)
+	}
+	if marked, _ := store.ToggleBookmark(4); marked {
+		t.Fatalf("wanted the bookmark of log 4 to be removed")
+	}
+	// bookmarks of overwritten logs can be removed
+	// but overwritten logs cannot be bookmarked
+	if marked, err := store.ToggleBookmark(0); err != nil || marked {
+		t.Fatalf("unable to remove the bookmark of log 0: %v", err)
+	}
+	if _, err := store.ToggleBookmark(0); err == nil {
+		t.Fatalf("wanted an error bookmarking an overwritten log")
+	}
+}
+
+func TestRestoreBookmarks(t *testing.T) {
+
+	previous := New(4)
+	for _, data := range []string{"zero", "one"} {
+		previous.Insert("api", time.Now(), []byte(data))
+	}
+	previous.ToggleBookmark(1)
+	previous.Annotate(1, "retry starts here")
+
+	var saved bytes.Buffer
+	if err := previous.SaveBookmarks(&saved); err != nil {
+		t.Fatalf("unable to save bookmarks: %v", err)
+	}
+
+	store := New(4)
+	if err := store.RestoreBookmarks(&saved); err != nil {
+		t.Fatalf("unable to restore bookmarks: %v", err)
+	}
+	for _, data := range []string{"new zero", "new one"} {
+		store.Insert("api", time.Now(), []byte(data))
+	}
+	store.ToggleBookmark(1)
+
+	list := store.Bookmarks()
+	if len(list) != 2 {
+		t.Fatalf("wanted 2 bookmarks, got: %d", len(list))
+	}
+	restored := list[0]
+	if !restored.Restored || !restored.Overwritten || restored.Raw != "one" || restored.Note != "retry starts here" {
+		t.Fatalf("wanted the bookmark of the previous session with its log and note, got: %+v", restored)
+	}
+	if list[1].Restored || list[1].Raw != "new one" {
+		t.Fatalf("wanted the bookmark of this session, got: %+v", list[1])
+	}
+
+	// the restored bookmark does not mark the
+	// log at its offset in this session
+	store.RemoveBookmark(restored)
+	if list := store.Bookmarks(); len(list) != 1 || list[0].Restored {
+		t.Fatalf("wanted only the bookmark of this session left, got: %+v", list)
+	}
+
+	if err := store.RestoreBookmarks(strings.NewReader("not json")); err == nil {
+		t.Fatalf("wanted an error restoring a broken file")
+	}
+}
+
+func TestCopyBookmarks(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(4, 50)
+	for _, data := range []string{"zero", "one", "two"} {
+		store.Insert("api", time.Now(), []byte(data))
+	}
+	store.ToggleBookmark(0)
+	store.ToggleBookmark(2)
+	store.Annotate(2, "retry starts here")
+
+	copied, err := formatter.Range(0, 2)
+	if err != nil {
+		t.Fatalf("unable to copy: %v", err)
+	}
+	if want := "# bookmark\nzero\none\n# bookmark: retry starts here\ntwo"; copied != want {
+		t.Fatalf("wanted the notes of the bookmarks copied, got: %q", copied)
+	}
+}
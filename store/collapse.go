@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CollapseMode decides whether a log repeating the previous log
+// of its beam is stored once more or counted as a repeat of it.
+// Health checks and retry loops take a single line that way.
+type CollapseMode int
+
+const (
+	// CollapseOff stores every log
+	CollapseOff CollapseMode = iota
+	// CollapseRepeats counts logs identical to the
+	// previous log of the beam as its repeats
+	CollapseRepeats
+	// CollapseTemplates counts logs which differ from the
+	// previous log of the beam only in numbers, UUIDs, hex
+	// ids or timestamps as its repeats
+	CollapseTemplates
+)
+
+// CollapseModes are the names of the modes
+// as used in the config
+var CollapseModes = []string{"off", "repeats", "templates"}
+
+// ParseCollapseMode maps the name of a CollapseMode
+// as used in the config to its CollapseMode
+func ParseCollapseMode(name string) (CollapseMode, error) {
+	switch name {
+	case "", "off":
+		return CollapseOff, nil
+	case "repeats":
+		return CollapseRepeats, nil
+	case "templates":
+		return CollapseTemplates, nil
+	}
+	return CollapseOff, fmt.Errorf("unknown collapse mode %q (options: %s)", name, strings.Join(CollapseModes, ", "))
+}
+
+// variables matches the parts of a log which vary between
+// logs of the same template. Timestamps are made of numbers.
+var variables = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0[xX][0-9a-fA-F]+|\b[0-9a-f]{12,}\b|[0-9]+`)
+
+// template returns the log with its variable parts replaced
+func template(raw string) string {
+	return variables.ReplaceAllLiteralString(raw, "#")
+}
+
+// collapser remembers the latest log of each beam such that
+// the next log of the beam can be collapsed into it
+type collapser struct {
+	labels map[string]CollapseMode
+	// fallback applies to beams
+	// without a mode of their own
+	fallback CollapseMode
+	latest   map[string]collapsed
+}
+
+// collapsed is the latest log of a beam
+type collapsed struct {
+	offset uint32
+	// key is what the next log is compared by
+	key string
+}
+
+// key returns what the log of the beam is compared by with the
+// previous log of the beam. ok is false if the beam's logs are
+// not collapsed.
+func (c collapser) key(label string, raw string) (string, bool) {
+	mode, ok := c.labels[label]
+	if !ok {
+		mode = c.fallback
+	}
+
+	switch mode {
+	case CollapseRepeats:
+		return raw, true
+	case CollapseTemplates:
+		return template(raw), true
+	}
+	return "", false
+}
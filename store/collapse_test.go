@@ -0,0 +1,108 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapse(t *testing.T) {
+
+	store := New(12)
+	store.SetCollapseMode("", CollapseRepeats)
+	store.SetCollapseMode("db", CollapseOff)
+	// wide enough for the repeat counter not to wrap
+	pager := store.NewPager(6, 60, testRefreshRate)
+
+	seen := time.Now()
+	for _, log := range []struct{ label, data string }{
+		{"api", "GET /health 200"},
+		{"api", "GET /health 200"},
+		{"web", "render /"},
+		{"api", "GET /health 200"},
+		{"db", "ping"},
+		{"db", "ping"},
+		{"api", "GET /users 200"},
+		{"api", "GET /health 200"},
+	} {
+		store.Insert(log.label, seen, []byte(log.data))
+		pager.MovePosition()
+	}
+	if !pager.stale {
+		t.Fatalf("wanted repeats to leave the page to be rebuilt by the next render")
+	}
+	pager.Refresh()
+	if pager.stale {
+		t.Fatalf("wanted the page to be rebuilt once rendered")
+	}
+
+	if _, latest, _ := store.buffer.Window(); latest != 5 {
+		t.Fatalf("wanted 6 logs stored, got: %d", latest+1)
+	}
+	if health := store.buffer.At(0); health.Repeats != 2 || !health.LastSeen.Equal(seen) {
+		t.Fatalf("wanted the health check repeated twice, got: %d", health.Repeats)
+	}
+	if users := store.buffer.At(4); users.Repeats != 0 {
+		t.Fatalf("wanted only consecutive logs of a beam to be collapsed, got: %d repeats", users.Repeats)
+	}
+
+	want := []string{
+		"api | ×3 last seen " + seen.Format(lastSeenLayout) + " GET /health 200",
+		"web | render /",
+		"db  | ping",
+		"db  | ping",
+		"api | GET /users 200",
+		"api | GET /health 200",
+	}
+	for i, line := range strings.Split(pager.String(), "\n") {
+		if printable(line) != want[i] {
+			t.Fatalf("wanted line %d to be %q, got: %q", i, want[i], printable(line))
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+
+	tt := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{
+			name: "numbers",
+			a:    "retry 1 of 5 in 200ms",
+			b:    "retry 2 of 5 in 400ms",
+			same: true,
+		},
+		{
+			name: "uuids",
+			a:    "request 3f2b8c1e-9a4d-4e5f-8b6a-1c2d3e4f5a6b done",
+			b:    "request a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d done",
+			same: true,
+		},
+		{
+			name: "timestamps",
+			a:    `{"ts":"2023-04-01T12:00:01.123Z","msg":"tick"}`,
+			b:    `{"ts":"2023-04-01T12:00:02.456Z","msg":"tick"}`,
+			same: true,
+		},
+		{
+			name: "hex ids",
+			a:    "span 0x1f3a trace 4bf92f3577b34da6a3ce929d0e0e4736",
+			b:    "span 0xbeef trace 00f067aa0ba902b7a3ce929d0e0e4736",
+			same: true,
+		},
+		{
+			name: "words",
+			a:    "GET /users 200",
+			b:    "GET /orders 200",
+			same: false,
+		},
+	}
+
+	for _, tc := range tt {
+		if same := template(tc.a) == template(tc.b); same != tc.same {
+			t.Fatalf("[%s] wanted same template: %t - got: %q and %q", tc.name, tc.same, template(tc.a), template(tc.b))
+		}
+	}
+}
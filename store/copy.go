@@ -0,0 +1,118 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Raw returns the data of count items starting with the
+// selected item. Evicted items are left out.
+func (formatter *Formatter) Raw(count int) (string, error) {
+	return formatter.Range(formatter.absolute, formatter.absolute+uint32(count)-1)
+}
+
+// Range returns the data of the items from the index from to
+// the index to (both included) separated by a newline. The
+// range is limited to the items present in the buffer.
+// Bookmarked items are preceded by a line holding the note
+// of the bookmark (# bookmark: note).
+func (formatter *Formatter) Range(from uint32, to uint32) (string, error) {
+
+	oldest, latest, ok := formatter.reader.Window()
+	if !ok {
+		return "", fmt.Errorf("no logs received yet")
+	}
+	if from > to {
+		from, to = to, from
+	}
+	if from < oldest {
+		from = oldest
+	}
+	if to > latest {
+		to = latest
+	}
+
+	var lines []string
+	for offset := from; offset <= to && offset >= from; offset++ {
+		item := formatter.reader.At(offset)
+		if item.Evicted {
+			continue
+		}
+		if bookmark, ok := formatter.bookmarks[offset]; ok {
+			lines = append(lines, renderCopied(bookmark))
+		}
+		lines = append(lines, item.Raw)
+	}
+
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no logs between %d and %d", from, to)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Pretty returns the selected item pretty printed as shown
+// in the modal (see formatPayload) but without colors. Data in
+// no known format is returned as is.
+func (formatter *Formatter) Pretty() (string, error) {
+	item := formatter.reader.At(formatter.absolute)
+	if item.Evicted {
+		return "", fmt.Errorf("log %d has been evicted", formatter.absolute)
+	}
+
+	pretty, _ := formatPayload(item.Raw, formatter.payloads[item.Label])
+	return stripANSI(pretty), nil
+}
+
+// Field returns the value of the field of the selected item.
+// Nested fields and array elements are separated by a dot
+// (user.roles.0). Strings are returned without quotes while
+// any other value is returned as JSON.
+func (formatter *Formatter) Field(path string) (string, error) {
+	item := formatter.reader.At(formatter.absolute)
+	if item.Evicted {
+		return "", fmt.Errorf("log %d has been evicted", formatter.absolute)
+	}
+	return lookupField(item.Raw, path)
+}
+
+func lookupField(data string, path string) (string, error) {
+
+	// numbers are kept as written in the log
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("log is not JSON")
+	}
+
+	for _, key := range strings.Split(strings.TrimSpace(path), ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return "", fmt.Errorf("field %q not found", path)
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("field %q not found", path)
+			}
+			value = v[i]
+		default:
+			return "", fmt.Errorf("field %q not found", path)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLookupField(t *testing.T) {
+
+	data := `{"msg":"done","ts":1692292122.983928,"user":{"id":42,"roles":["admin","dev"]},"ok":true}`
+
+	tt := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "msg", want: "done"},
+		{path: "ts", want: "1692292122.983928"},
+		{path: "user.id", want: "42"},
+		{path: "user.roles.1", want: "dev"},
+		{path: "user.roles", want: `["admin","dev"]`},
+		{path: "ok", want: "true"},
+		{path: "user.name", wantErr: true},
+		{path: "user.roles.2", wantErr: true},
+		{path: "msg.length", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		got, err := lookupField(data, tc.path)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("[%s] wanted error: %t, got: %v", tc.path, tc.wantErr, err)
+		}
+		if got != tc.want {
+			t.Fatalf("[%s] wanted: %q, got: %q", tc.path, tc.want, got)
+		}
+	}
+
+	if _, err := lookupField("level=info msg=done", "msg"); err == nil {
+		t.Fatalf("wanted error for a log which is not JSON")
+	}
+}
+
+func TestCopyRange(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(4, 50)
+
+	for i := 0; i < 6; i++ {
+		store.Insert("test", time.Now(), []byte(fmt.Sprintf(`{"index":%d}`, i)))
+	}
+
+	formatter.Load(3)
+
+	tt := []struct {
+		name string
+		copy func() (string, error)
+		want string
+	}{
+		{name: "selected", copy: func() (string, error) { return formatter.Raw(1) }, want: `{"index":3}`},
+		{name: "count", copy: func() (string, error) { return formatter.Raw(2) }, want: "{\"index\":3}\n{\"index\":4}"},
+		{name: "count beyond latest", copy: func() (string, error) { return formatter.Raw(10) }, want: "{\"index\":3}\n{\"index\":4}\n{\"index\":5}"},
+		{name: "range beyond oldest", copy: func() (string, error) { return formatter.Range(0, 2) }, want: `{"index":2}`},
+		{name: "reversed range", copy: func() (string, error) { return formatter.Range(5, 4) }, want: "{\"index\":4}\n{\"index\":5}"},
+		{name: "pretty", copy: formatter.Pretty, want: "{\n  \"index\": 3\n}"},
+	}
+
+	for _, tc := range tt {
+		got, err := tc.copy()
+		if err != nil {
+			t.Fatalf("[%s] unable to copy: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("[%s] wanted: %q, got: %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCopyPretty(t *testing.T) {
+
+	store := New(4)
+	store.FormatPayloadAs("app", "logfmt")
+	formatter := store.NewFormatter(4, 50)
+
+	store.Insert("api", time.Now(), []byte(`{"msg":"done","body":"{\"id\":7}"}`))
+	store.Insert("app", time.Now(), []byte(`level=info msg=done`))
+
+	tt := []struct {
+		name  string
+		index int
+		want  string
+	}{
+		{name: "embedded json", index: 0, want: "{\n  \"body\": {\n    \"id\": 7\n  },\n  \"msg\": \"done\"\n}"},
+		{name: "forced format", index: 1, want: "level = info\nmsg   = done"},
+	}
+
+	for _, tc := range tt {
+		formatter.Load(tc.index)
+		got, err := formatter.Pretty()
+		if err != nil {
+			t.Fatalf("[%s] unable to copy: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("[%s] wanted: %q, got: %q", tc.name, tc.want, got)
+		}
+	}
+}
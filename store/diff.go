@@ -0,0 +1,276 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/app/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// change is a difference between two logs
+type change struct {
+	// kind is one of "+" (added), "-" (removed)
+	// or "~" (changed)
+	kind string
+	// path of the changed value (JSONPath) or
+	// empty for a line of a line diff
+	path     string
+	old, new string
+}
+
+// diffJSON compares two decoded JSON values and returns
+// the keys and array elements added, removed or changed
+// with the keys of objects in alphabetical order
+func diffJSON(path string, a, b interface{}) []change {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a)+len(b))
+		for key := range a {
+			keys = append(keys, key)
+		}
+		for key := range b {
+			if _, ok := a[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var changes []change
+		for _, key := range keys {
+			childPath := path + "." + key
+			if !identifier.MatchString(key) {
+				childPath = path + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"
+			}
+			va, inA := a[key]
+			vb, inB := b[key]
+			switch {
+			case !inA:
+				changes = append(changes, change{kind: "+", path: childPath, new: compact(vb)})
+			case !inB:
+				changes = append(changes, change{kind: "-", path: childPath, old: compact(va)})
+			default:
+				changes = append(changes, diffJSON(childPath, va, vb)...)
+			}
+		}
+		return changes
+
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		var changes []change
+		for i := 0; i < len(a) || i < len(b); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				changes = append(changes, change{kind: "+", path: childPath, new: compact(b[i])})
+			case i >= len(b):
+				changes = append(changes, change{kind: "-", path: childPath, old: compact(a[i])})
+			default:
+				changes = append(changes, diffJSON(childPath, a[i], b[i])...)
+			}
+		}
+		return changes
+	}
+
+	if before, after := compact(a), compact(b); before != after {
+		return []change{{kind: "~", path: path, old: before, new: after}}
+	}
+	return nil
+}
+
+// compact returns the value as JSON
+func compact(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
+
+// diffLines compares the lines of a and b keeping
+// the longest common lines. Logs of a single line
+// are compared by their space separated words.
+func diffLines(a, b string) []change {
+	sep := "\n"
+	if !strings.Contains(a, "\n") && !strings.Contains(b, "\n") {
+		sep = " "
+	}
+	x, y := strings.Split(a, sep), strings.Split(b, sep)
+
+	// lcs[i][j] is the length of the longest
+	// common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changes []change
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			changes = append(changes, change{kind: " ", old: x[i], new: y[j]})
+			i++
+			j++
+		case j < len(y) && (i >= len(x) || lcs[i][j+1] > lcs[i+1][j]):
+			changes = append(changes, change{kind: "+", new: y[j]})
+			j++
+		default:
+			changes = append(changes, change{kind: "-", old: x[i]})
+			i++
+		}
+	}
+	return changes
+}
+
+// diff compares the data of two logs structurally if
+// both are JSON (with embedded JSON expanded) or else
+// line by line
+func diff(a, b string) ([]change, bool) {
+	var va, vb interface{}
+	decA := json.NewDecoder(strings.NewReader(a))
+	decA.UseNumber()
+	decB := json.NewDecoder(strings.NewReader(b))
+	decB.UseNumber()
+	if decA.Decode(&va) == nil && decB.Decode(&vb) == nil {
+		return diffJSON("$", expandJSON(va), expandJSON(vb)), true
+	}
+	return diffLines(a, b), false
+}
+
+// MarkDiff marks the selected log to be compared with
+// the log selected once the diff is shown
+func (formatter *Formatter) MarkDiff() error {
+	item := formatter.reader.At(formatter.absolute)
+	if item.Index() == 0 || item.Evicted {
+		return fmt.Errorf("log %d cannot be marked", formatter.absolute)
+	}
+	offset := formatter.absolute
+	formatter.marked = &offset
+	formatter.buildView()
+	return nil
+}
+
+// Marked returns the offset of the log marked to be diffed
+func (formatter *Formatter) Marked() (uint32, bool) {
+	if formatter.marked == nil {
+		return 0, false
+	}
+	return *formatter.marked, true
+}
+
+// ShowDiff shows the differences between the marked and the
+// selected log in the modal. Selecting another log while the
+// diff is shown compares the marked log with it.
+func (formatter *Formatter) ShowDiff() error {
+	if formatter.marked == nil {
+		return fmt.Errorf("mark a log to diff first")
+	}
+	if *formatter.marked == formatter.absolute {
+		return fmt.Errorf("select another log to diff with %d", formatter.absolute)
+	}
+	formatter.diffing = true
+	formatter.buildForeground()
+	return nil
+}
+
+// CloseDiff hides the diff and removes the mark
+func (formatter *Formatter) CloseDiff() {
+	formatter.diffing = false
+	formatter.marked = nil
+	formatter.buildView()
+}
+
+// Diffing reports whether the diff is shown
+func (formatter *Formatter) Diffing() bool {
+	return formatter.diffing
+}
+
+// buildDiffForeground shows the changes from the marked
+// log to the selected log in the modal. Changes not fitting
+// the page are counted in the last line.
+func (formatter *Formatter) buildDiffForeground() {
+	width := modalWidth(formatter.ttyWidth)
+	style := modalStyle.Copy().
+		BorderForeground(styles.Current().Modal).
+		Width(width)
+
+	from := formatter.reader.At(*formatter.marked)
+	to := formatter.reader.At(formatter.absolute)
+	if from.Evicted || to.Evicted {
+		formatter.foreground = style.Render(evictedStyle.Render("a log of the diff has been evicted"))
+		return
+	}
+
+	changes, structural := diff(from.Raw, to.Raw)
+	theme := styles.Current()
+	added := lipgloss.NewStyle().Foreground(theme.Modes.Following)
+	removed := lipgloss.NewStyle().Foreground(theme.Error)
+	changed := lipgloss.NewStyle().Foreground(theme.Highlight)
+
+	// the padding takes a column on either side
+	inner := width - 2
+	var lines []string
+	for _, c := range changes {
+		var line string
+		switch {
+		case !structural && c.kind == " ":
+			line = fit("  "+c.old, inner)
+		case !structural && c.kind == "+":
+			line = added.Render(fit("+ "+c.new, inner))
+		case !structural:
+			line = removed.Render(fit("- "+c.old, inner))
+		case c.kind == "+":
+			line = added.Render(fit("+ "+c.path+": "+c.new, inner))
+		case c.kind == "-":
+			line = removed.Render(fit("- "+c.path+": "+c.old, inner))
+		default:
+			line = changed.Render(fit("~ "+c.path+": "+c.old+" → "+c.new, inner))
+		}
+		lines = append(lines, line)
+	}
+	if structural && len(changes) == 0 {
+		lines = append(lines, treeInfoStyle.Render("no differences"))
+	}
+
+	// borders, headers and the summary
+	height := int(formatter.size) - 5
+	if height < 1 {
+		height = 1
+	}
+	if len(lines) > height {
+		more := len(lines) - height + 1
+		lines = append(lines[:height-1], treeInfoStyle.Render(fmt.Sprintf("… %d more", more)))
+	}
+
+	kind := "line diff"
+	if structural {
+		kind = fmt.Sprintf("%d changes", len(changes))
+	}
+	formatter.foreground = style.Render(lipgloss.JoinVertical(lipgloss.Left,
+		removed.Render(fmt.Sprintf("- %d ", *formatter.marked))+formatter.prefix.render(from),
+		added.Render(fmt.Sprintf("+ %d ", formatter.absolute))+formatter.prefix.render(to),
+		treeInfoStyle.Render(kind),
+		strings.Join(lines, "\n"),
+	))
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+
+	tt := []struct {
+		name       string
+		a, b       string
+		structural bool
+		want       []string
+	}{
+		{
+			name:       "json",
+			a:          `{"status":200,"user":{"id":7,"roles":["a","b"]},"error":"{\"code\":1}"}`,
+			b:          `{"status":500,"user":{"id":7,"roles":["a"]},"error":"{\"code\":2}","retry":true}`,
+			structural: true,
+			want: []string{
+				"~ $.error.code 1 2",
+				"+ $.retry  true",
+				"~ $.status 200 500",
+				`- $.user.roles[1] "b" `,
+			},
+		},
+		{
+			name:       "equal json",
+			a:          `{"a":1,"b":[1,2]}`,
+			b:          `{"b":[1,2],"a":1}`,
+			structural: true,
+		},
+		{
+			name: "words of a line",
+			a:    `level=info msg=ok took=3ms`,
+			b:    `level=error msg=ok took=3ms`,
+			want: []string{"- level=info ", "+  level=error", " msg=ok msg=ok", " took=3ms took=3ms"},
+		},
+		{
+			name: "lines",
+			a:    "panic: boom\nmain.go:12\nmain.go:40",
+			b:    "panic: boom\nmain.go:40",
+			want: []string{" panic: boom panic: boom", "- main.go:12 ", " main.go:40 main.go:40"},
+		},
+	}
+
+	for _, tc := range tt {
+		changes, structural := diff(tc.a, tc.b)
+		if structural != tc.structural {
+			t.Fatalf("[%s] wanted structural: %t, got: %t", tc.name, tc.structural, structural)
+		}
+
+		var got []string
+		for _, c := range changes {
+			got = append(got, strings.TrimSpace(c.kind+" "+c.path)+" "+c.old+" "+c.new)
+		}
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Fatalf("[%s] wanted:\n%q\ngot:\n%q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestShowDiff(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(12, 80)
+
+	store.Insert("api", time.Now(), []byte(`{"status":200}`))
+	store.Insert("api", time.Now(), []byte(`{"status":500}`))
+	formatter.Load(0)
+
+	if err := formatter.ShowDiff(); err == nil {
+		t.Fatalf("wanted an error without a marked log")
+	}
+	if err := formatter.MarkDiff(); err != nil {
+		t.Fatalf("unable to mark the log: %v", err)
+	}
+	if err := formatter.ShowDiff(); err == nil {
+		t.Fatalf("wanted an error diffing the marked log with itself")
+	}
+
+	formatter.Next()
+	if err := formatter.ShowDiff(); err != nil {
+		t.Fatalf("unable to show the diff: %v", err)
+	}
+	if !strings.Contains(formatter.foreground, "~ $.status: 200 → 500") {
+		t.Fatalf("wanted the changed status in the modal, got:\n%s", formatter.foreground)
+	}
+
+	formatter.CloseDiff()
+	if _, ok := formatter.Marked(); ok || formatter.Diffing() {
+		t.Fatalf("wanted the diff to be closed and the mark removed")
+	}
+}
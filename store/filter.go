@@ -0,0 +1,60 @@
+package store
+
+// filter decides which beams are shown by the pagers of
+// a store. Hidden items are still buffered and can be
+// browsed with a formatter.
+type filter struct {
+	muted map[string]bool
+	// solo is the only label shown if set
+	solo string
+}
+
+func newFilter() *filter {
+	return &filter{
+		muted: make(map[string]bool),
+	}
+}
+
+func (f *filter) hidden(label string) bool {
+	if f.solo != "" {
+		return label != f.solo
+	}
+	return f.muted[label]
+}
+
+// Mute hides the logs of the beam from the follow view
+// or shows them again if already muted. Reports whether
+// the beam is muted afterwards.
+func (store *Store) Mute(label string) bool {
+	store.filter.muted[label] = !store.filter.muted[label]
+	return store.filter.muted[label]
+}
+
+// Solo hides the logs of all other beams from the follow
+// view or shows them again if the beam is already solo.
+// Reports whether the beam is solo afterwards.
+func (store *Store) Solo(label string) bool {
+	if store.filter.solo == label {
+		store.filter.solo = ""
+		return false
+	}
+	store.filter.solo = label
+	return true
+}
+
+// Hidden reports whether the logs of the beam are
+// currently hidden from the follow view
+func (store *Store) Hidden(label string) bool {
+	return store.filter.hidden(label)
+}
+
+// Only restricts the pager to the logs of the beam. Muted
+// and solo beams of the store are still hidden.
+func (pager *Pager) Only(label string) {
+	pager.only = label
+}
+
+// hidden reports whether the pager skips the logs of the beam
+func (pager *Pager) hidden(label string) bool {
+	return pager.filter.hidden(label) || (pager.only != "" && label != pager.only)
+}
@@ -1,13 +1,13 @@
 package store
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/KonstantinGasser/scotty/app/styles"
 	"github.com/KonstantinGasser/scotty/store/ring"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hokaccha/go-prettyjson"
-	"github.com/muesli/ansi"
 	"github.com/muesli/reflow/wrap"
 )
 
@@ -31,6 +31,27 @@ func init() {
 
 type Formatter struct {
 	reader ring.Reader
+	// prefix renders the line prefix of each item
+	prefix *prefixer
+	// payloads maps beams to the format
+	// their logs are pretty printed in
+	payloads map[string]string
+	// bookmarks to jump between
+	bookmarks bookmarks
+	// tree explores the selected log if open
+	tree *tree
+	// pinned paths of the tree stay expanded
+	// while moving through the logs
+	pinned map[string]bool
+	// marked is the offset of the log the selected
+	// log is compared with while diffing is true
+	marked  *uint32
+	diffing bool
+	// nowrap cuts the log in the modal at its width
+	// instead of wrapping it; column is the first
+	// column of the data shown
+	nowrap bool
+	column int
 	// page size - max number of items
 	// which can be placed on the page
 	// without any of them being formatted.
@@ -63,10 +84,29 @@ func (formatter Formatter) CurrentIndex() uint32 {
 	return formatter.absolute
 }
 
+// Load loads the page starting at the offset. Offsets the
+// buffer has overwritten or not yet written are not read:
+// the page starts at the oldest or latest log instead and
+// slots past the latest log stay empty.
 func (formatter *Formatter) Load(start int) {
 
+	if oldest, latest, ok := formatter.reader.Window(); ok {
+		if start < int(oldest) {
+			start = int(oldest)
+		}
+		if start > int(latest) {
+			start = int(latest)
+		}
+	}
+
 	formatter.buffer = make([]ring.Item, formatter.size)
 	formatter.reader.OffsetRead(start, formatter.buffer)
+	// the read wraps around the end of the buffer
+	for i, item := range formatter.buffer {
+		if item.Index() != uint32(start+i)+1 {
+			formatter.buffer[i] = ring.Item{}
+		}
+	}
 
 	formatter.relative = 0 // make the first item of the buffer be the absolute item
 	formatter.absolute = uint32(start)
@@ -74,41 +114,87 @@ func (formatter *Formatter) Load(start int) {
 	formatter.buildView()
 }
 
+// LoadTarget loads the page starting at the target
+func (formatter *Formatter) LoadTarget(target Target) error {
+	offset, err := target.resolve(formatter.reader)
+	if err != nil {
+		return err
+	}
+
+	formatter.Load(int(offset))
+	return nil
+}
+
+// ToggleIndex shows or hides the index gutter
+func (formatter *Formatter) ToggleIndex() {
+	formatter.prefix.index = !formatter.prefix.index
+	formatter.buildView()
+}
+
+// ToggleTime cycles through the time modes of
+// the line prefix
+func (formatter *Formatter) ToggleTime() {
+	formatter.prefix.toggleTime()
+	formatter.buildView()
+}
+
 func (formatter *Formatter) Next() {
+	formatter.Move(1)
+}
+
+func (formatter *Formatter) Privous() {
+	formatter.Move(-1)
+}
+
+// Move selects the item delta items after the selected item
+// (before if delta is negative). The selection stops at the
+// oldest and latest item of the buffer. The page is turned
+// such that the selected item is the first of the page once
+// the item is not on the current page.
+func (formatter *Formatter) Move(delta int) {
 
-	if !formatter.reader.HasData(formatter.absolute) {
+	oldest, latest, ok := formatter.reader.Window()
+	if !ok {
 		return
 	}
 
-	formatter.absolute += 1
-	// turn page forward by formatter.size
-	if formatter.relative+1 > formatter.size {
-		formatter.reader.OffsetRead(int(formatter.absolute), formatter.buffer)
-		formatter.relative = 0
+	target := int(formatter.absolute) + delta
+	if target < int(oldest) {
+		target = int(oldest)
+	}
+	if target > int(latest) {
+		target = int(latest)
+	}
+	if target == int(formatter.absolute) {
+		return
+	}
 
-		formatter.buildView()
+	relative := int(formatter.relative) + target - int(formatter.absolute)
+	if relative < 0 || relative >= int(formatter.size) {
+		formatter.Load(target)
 		return
 	}
 
-	formatter.relative += 1
+	formatter.absolute = uint32(target)
+	formatter.relative = uint8(relative)
 	formatter.buildView()
 }
 
-func (formatter *Formatter) Privous() {
+// SelectAt selects the item shown in the row of the page
+// unless the point (x, row) lies within the modal showing
+// the selected item
+func (formatter *Formatter) SelectAt(x int, row int) {
 
-	if formatter.absolute-1 < 0 {
+	x0, y0 := formatter.modalOrigin()
+	if x >= x0 && x < x0+lipgloss.Width(formatter.foreground) &&
+		row >= y0 && row < y0+lipgloss.Height(formatter.foreground) {
 		return
 	}
 
-	formatter.absolute -= 1
-	if formatter.relative == 0 {
-		formatter.reader.OffsetRead(int(formatter.absolute), formatter.buffer)
-		formatter.buildView()
+	if row < 0 || row >= len(formatter.buffer) || formatter.buffer[row].Index() == 0 {
 		return
 	}
-
-	formatter.relative -= 1
-	formatter.buildView()
+	formatter.Move(row - int(formatter.relative))
 }
 
 // assumes that the buffer has the correct data
@@ -118,8 +204,24 @@ func (formatter *Formatter) buildView() {
 }
 
 var selected = ">>>"
+
+// marked prefixes the log marked to be diffed
+var marked = "<->"
 var trimmedSuffix = "..."
 
+// truncatedMargin are the columns left free
+// right of a truncated log
+const truncatedMargin = 2
+
+// evictedNote replaces the data of items evicted
+// to stay within the memory limits of the buffer
+var evictedNote = "%s | <evicted>"
+var evictedStyle = lipgloss.NewStyle().Faint(true)
+
+// formatStyle renders the format the log is
+// pretty printed in next to the prefix
+var formatStyle = lipgloss.NewStyle().Faint(true)
+
 // TODO: range of lines not buffer. Buffer might be longer
 // than the lines (like after a call to Resize where the dims change).
 // Also if buffer > lines running index should be i := buffer - lines
@@ -128,38 +230,36 @@ func (formatter *Formatter) buildBackground() {
 
 	var lines = make([]string, formatter.size)
 
-	var printable, ansiEsc int
 	var raw strings.Builder
 	for i, item := range formatter.buffer {
 
 		raw = strings.Builder{}
 
-		// index of an item in this case
-		// may never be zero as we start at 1
-		// for the index shown to the user.
-		// There are other places where an index of
-		// zero does not indicated that the item is
-		// the zero value Item{}
-		// if item.Index() > 0 {
-		// 	raw.WriteString(fmt.Sprintf("[%d]", item.Index()-1))
-		// }
-
 		if i == int(formatter.relative) {
 			raw.WriteString(selected)
+		} else if formatter.marked != nil && item.Index() == *formatter.marked+1 {
+			raw.WriteString(marked)
 		}
 
-		raw.WriteString(item.Raw)
-
-		printable = ansi.PrintableRuneWidth(raw.String())
-		ansiEsc = len(raw.String()) - printable
-
-		if printable > formatter.ttyWidth {
-			tmp := raw.String()[:formatter.ttyWidth+(ansiEsc-1)]
-			lines[i] = tmp[:len(tmp)-1-len(trimmedSuffix)] + trimmedSuffix
+		if item.Evicted {
+			raw.WriteString(evictedStyle.Render(fmt.Sprintf(evictedNote, item.Label)))
+		}
+		raw.WriteString(formatter.prefix.render(item))
+		if formatter.nowrap {
+			lines[i] = clipLine(raw.String(), item.Display(), formatter.column, formatter.ttyWidth)
 			continue
 		}
-
-		lines[i] = raw.String()
+		raw.WriteString(item.Display())
+
+		line := raw.String()
+		switch {
+		case displayWidth(line) > formatter.ttyWidth:
+			line = clip(line, 0, formatter.ttyWidth-truncatedMargin-len(trimmedSuffix)) + trimmedSuffix
+		// a log takes a single row
+		case strings.ContainsAny(line, "\r\n\t"):
+			line = clip(line, 0, formatter.ttyWidth)
+		}
+		lines[i] = line
 		raw.Reset()
 	}
 	formatter.background = strings.Join(lines, "\n")
@@ -186,26 +286,90 @@ func (formatter *Formatter) buildForeground() {
 
 	item := formatter.reader.At(uint32(formatter.absolute))
 
-	pretty, err := jsonF.Format(
-		[]byte(item.Raw[item.DataPointer:]),
-	)
+	if item.Evicted {
+		formatter.foreground = modalStyle.Copy().
+			BorderForeground(styles.Current().Modal).
+			Width(modalWidth(formatter.ttyWidth)).
+			Render(evictedStyle.Render(fmt.Sprintf(evictedNote, item.Label)))
+		return
+	}
 
-	if err != nil {
-		pretty = []byte(item.Raw[item.DataPointer:])
+	if formatter.diffing {
+		formatter.buildDiffForeground()
+		return
+	}
+
+	if formatter.tree != nil {
+		formatter.buildTreeForeground(item)
+		return
 	}
 
-	broken := wrap.Bytes(pretty, modalWidth(formatter.ttyWidth))
+	pretty, format := formatPayload(item.Raw, formatter.payloads[item.Label])
+
+	var broken string
+	if formatter.nowrap {
+		// the padding takes a column on either side
+		broken = clipLines(pretty, formatter.column, modalWidth(formatter.ttyWidth)-2)
+	} else {
+		broken = wrap.String(pretty, modalWidth(formatter.ttyWidth))
+	}
+
+	header := formatter.prefix.render(item)
+	// JSON is expected and not worth noting
+	if format != "" && format != "json" {
+		header += formatStyle.Render(format)
+	}
+	if bookmark, ok := formatter.bookmarks[formatter.absolute]; ok && bookmark.Note != "" {
+		header += " " + renderNote(bookmark.Note)
+	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
-		item.Raw[:item.DataPointer],
-		string(broken),
+		header,
+		broken,
 	)
 
-	formatter.foreground = modalStyle.
+	formatter.foreground = modalStyle.Copy().
+		BorderForeground(styles.Current().Modal).
 		Width(modalWidth(formatter.ttyWidth)).
 		Render(content)
 }
 
+// buildTreeForeground shows the tree of the selected log in
+// the modal. The tree is rebuilt once another log is selected
+// keeping the selected path and pinned paths. Logs which are
+// not JSON are pretty printed as usual.
+func (formatter *Formatter) buildTreeForeground(item ring.Item) {
+	if formatter.tree.index != formatter.absolute {
+		path := formatter.tree.selected().path
+		if err := formatter.buildTree(path); err != nil {
+			formatter.tree.index = formatter.absolute
+			formatter.tree.root = nil
+		}
+	}
+
+	width := modalWidth(formatter.ttyWidth)
+	style := modalStyle.Copy().
+		BorderForeground(styles.Current().Modal).
+		Width(width)
+
+	if formatter.tree.root == nil {
+		formatter.foreground = style.Render(lipgloss.JoinVertical(lipgloss.Left,
+			formatter.prefix.render(item),
+			treeInfoStyle.Render("not JSON - no tree to explore"),
+			wrap.String(item.Raw, width),
+		))
+		return
+	}
+
+	// the padding takes a column on either side
+	inner := width - 2
+	formatter.foreground = style.Render(lipgloss.JoinVertical(lipgloss.Left,
+		formatter.prefix.render(item),
+		formatter.tree.render(inner, formatter.treeHeight(), formatter.pinned),
+		formatter.tree.info(inner),
+	))
+}
+
 func (formatter *Formatter) Reset(width int, height uint8) {
 	formatter.ttyWidth = width
 	formatter.size = height
@@ -223,9 +387,15 @@ func (formatter *Formatter) Resize(width int, height uint8) {
 
 func (formatter Formatter) String() string {
 
+	x0, y0 := formatter.modalOrigin()
+	return styles.Overlay(x0, y0, formatter.foreground, formatter.background, false)
+}
+
+// modalOrigin returns the top left corner of the
+// modal centered on the page
+func (formatter Formatter) modalOrigin() (int, int) {
 	modalWidth, modalHeight := lipgloss.Width(formatter.foreground), lipgloss.Height(formatter.foreground)
 	x0 := int(formatter.ttyWidth/2) - int(modalWidth/2)
 	y0 := int(formatter.size/2) - int(modalHeight/2)
-
-	return styles.Overlay(x0, y0, formatter.foreground, formatter.background, false)
+	return x0, y0
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBuildView(t *testing.T) {
@@ -20,9 +21,7 @@ func TestBuildView(t *testing.T) {
 	testLog := `{"hello": "world", "level": "debug", "index": {index}}`
 	for i := 0; i < fill; i++ {
 		log := strings.Replace(testLog, "{index}", fmt.Sprint(i), 1)
-		raw := testLabel + " | " + log
-
-		store.Insert(testLabel, len(testLabel+" | "), []byte(raw))
+		store.Insert(testLabel, time.Now(), []byte(log))
 	}
 
 	formatter.Load(1)
@@ -71,9 +70,7 @@ func TestBuildViewAfterResize(t *testing.T) {
 	testLog := `{"hello": "world", "level": "debug", "index": {index}}`
 	for i := 0; i < fill; i++ {
 		log := strings.Replace(testLog, "{index}", fmt.Sprint(i), 1)
-		raw := testLabel + " | " + log
-
-		store.Insert(testLabel, len(testLabel+" | "), []byte(raw))
+		store.Insert(testLabel, time.Now(), []byte(log))
 	}
 
 	formatter.Load(1)
@@ -125,3 +122,30 @@ func TestBuildViewAfterResize(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadOverwritten(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(4, 50)
+
+	for i := 0; i < 6; i++ {
+		store.Insert("test", time.Now(), []byte(fmt.Sprintf("log %d", i)))
+	}
+
+	// logs 0 and 1 have been overwritten
+	formatter.Load(0)
+	if formatter.CurrentIndex() != 2 {
+		t.Fatalf("wanted the oldest log 2 to be selected, got: %d", formatter.CurrentIndex())
+	}
+
+	// the page must not wrap around to the overwritten logs
+	formatter.Load(4)
+	for i, item := range formatter.buffer {
+		if i < 2 && item.Raw != fmt.Sprintf("log %d", 4+i) {
+			t.Fatalf("wanted log %d on row %d, got: %q", 4+i, i, item.Raw)
+		}
+		if i >= 2 && item.Index() != 0 {
+			t.Fatalf("wanted row %d to be empty, got: %q", i, item.Raw)
+		}
+	}
+}
@@ -5,79 +5,296 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/KonstantinGasser/scotty/store/ring"
-	"github.com/muesli/ansi"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
 const (
 	indentSuffix = "| "
+	escape       = '\x1b'
+	// variationEmoji asks for the emoji presentation
+	// of the previous rune which takes two columns
+	variationEmoji = '\ufe0f'
+	// regional indicators A to Z of which
+	// pairs make up a flag
+	regionalIndicatorA = '\U0001F1E6'
+	regionalIndicatorZ = '\U0001F1FF'
 )
 
 var (
 	builders sync.Pool = sync.Pool{New: func() any { return bytes.NewBuffer(nil) }}
 )
 
-func lineWrap(item ring.Item, ttyWidth int) []string {
+// segment is an escape sequence or a grapheme
+// cluster of a string
+type segment struct {
+	text string
+	// width is the number of columns the
+	// segment takes in the terminal
+	width   int
+	escape  bool
+	newline bool
+}
+
+// segments calls fn for each escape sequence and grapheme
+// cluster of s until fn returns false. Tabs are replaced by a
+// space, other control characters but newlines are dropped
+// as the terminal would not show them in a single column.
+func segments(s string, fn func(seg segment) bool) {
+	for len(s) > 0 {
+		if s[0] == escape {
+			n := escapeLen(s)
+			if !fn(segment{text: s[:n], escape: true}) {
+				return
+			}
+			s = s[n:]
+			continue
+		}
+
+		end := strings.IndexByte(s, escape)
+		if end < 0 {
+			end = len(s)
+		}
+		if !textSegments(s[:end], fn) {
+			return
+		}
+		s = s[end:]
+	}
+}
+
+// textSegments calls fn for each grapheme cluster
+// of the text without escape sequences
+func textSegments(text string, fn func(seg segment) bool) bool {
+	if isASCII(text) {
+		for i := 0; i < len(text); i++ {
+			if !fn(controlSegment(text[i:i+1], 1)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	graphemes := uniseg.NewGraphemes(text)
+	for graphemes.Next() {
+		cluster := graphemes.Str()
+		if !fn(controlSegment(cluster, clusterWidth(cluster, graphemes.Runes()))) {
+			return false
+		}
+	}
+	return true
+}
+
+// controlSegment returns the segment of the cluster
+// replacing control characters
+func controlSegment(cluster string, width int) segment {
+	switch {
+	// "\r\n" is a single cluster
+	case strings.HasSuffix(cluster, "\n"):
+		return segment{newline: true}
+	case cluster == "\t":
+		return segment{text: " ", width: 1}
+	case len(cluster) == 1 && (cluster[0] < ' ' || cluster[0] == 0x7f):
+		return segment{}
+	}
+	return segment{text: cluster, width: width}
+}
+
+// clusterWidth returns the columns a grapheme cluster
+// takes which is the width of its first rune with a
+// width unless the emoji presentation is asked for.
+// Flags (pairs of regional indicators) take two columns.
+func clusterWidth(cluster string, runes []rune) int {
+	width := runewidth.StringWidth(cluster)
+	if width != 1 || len(runes) < 2 {
+		return width
+	}
+	if runes[1] == variationEmoji || isRegionalIndicator(runes[0]) {
+		return 2
+	}
+	return width
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorA && r <= regionalIndicatorZ
+}
+
+// escapeLen returns the length of the escape sequence s
+// starts with. Unterminated sequences take the rest of s.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameters followed by a final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// OSC: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == escape && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
 
-	truePrefixLen := ansi.PrintableRuneWidth(item.Raw[:item.DataPointer])
-	// here we could do things better..how to avoid the string concadination?
-	indent := strings.Repeat(" ", clamp(truePrefixLen-len(indentSuffix))) + indentSuffix
+// isASCII reports whether s only has ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// isPrintableASCII reports whether s only has printable
+// ASCII characters each taking a single column
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// displayWidth returns the number of columns s takes
+// in the terminal not counting escape sequences
+func displayWidth(s string) int {
+	if isPrintableASCII(s) {
+		return len(s)
+	}
+	var width int
+	segments(s, func(seg segment) bool {
+		width += seg.width
+		return true
+	})
+	return width
+}
+
+// sgrState tracks the SGR sequences (colors and text
+// attributes) in effect at a point of a string
+type sgrState []string
+
+// apply updates the state with the escape sequence.
+// Sequences other than SGR do not change the state.
+func (state sgrState) apply(seq string) sgrState {
+	if len(seq) < 3 || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return state
+	}
+	params := seq[2 : len(seq)-1]
+	switch {
+	case params == "" || params == "0":
+		return state[:0]
+	case strings.HasPrefix(params, "0;"):
+		return append(state[:0], seq)
+	}
+	return append(state, seq)
+}
 
-	if len(item.Raw[item.DataPointer:])+truePrefixLen <= ttyWidth {
-		return []string{item.Raw}
+// lineWrap breaks the prefixed data into lines of at most ttyWidth
+// columns. Each but the first line is indented to the width of the
+// prefix. Lines are broken between grapheme clusters such that no
+// rune, emoji or escape sequence is split; colors of the data in
+// effect at the end of a line are reset and continued on the next.
+// Newlines of the data start a new line.
+func lineWrap(prefix string, data string, ttyWidth int) []string {
+
+	prefixWidth := displayWidth(prefix)
+	indent := strings.Repeat(" ", clamp(prefixWidth-len(indentSuffix))) + indentSuffix
+
+	if isPrintableASCII(data) {
+		return wrapASCII(prefix, data, prefixWidth, indent, ttyWidth)
 	}
 
-	// shows better results for B/op and maintains allocations (which have decreased by 1)
-	// however there is no free lunch and ns/op increase on average by 100ns while dividing the B/op by 2 thou
 	var builder = builders.Get().(*bytes.Buffer)
 	defer func() {
 		builder.Reset()
 		builders.Put(builder)
 	}()
 
-	// in order to minimize runtime.growslice and
-	// runtime.movemem calls we estimate how big
-	// the builder's buffer has to be by using the number of characters
-	// from the item paramter. However, we need to
-	// take new line chars in account which is why
-	// we add + len(item.Raw)/ttyWidth to the buffer size.
-	// Lastly, each second+ row has an inden prefix of the
-	// length of the line prefix which we need to add as well.
-	builder.Grow(len(item.Raw) + len(item.Raw)/ttyWidth + (clamp(int(len(item.Raw)/ttyWidth)-1) * len(indent)))
+	// estimate of the lines assuming a column per byte
+	lines := make([]string, 0, 1+len(data)/clamp1(ttyWidth-len(indent)))
 
-	ansiSeqLen := len(item.Raw[:item.DataPointer]) - truePrefixLen
+	var state sgrState
+	builder.WriteString(prefix)
+	col, start := prefixWidth, prefixWidth
 
-	var left, right = 0, ttyWidth
+	breakLine := func() {
+		if len(state) > 0 {
+			builder.WriteString(resetSequence)
+		}
+		lines = append(lines, builder.String())
+		builder.Reset()
+		builder.WriteString(indent)
+		for _, seq := range state {
+			builder.WriteString(seq)
+		}
+		col, start = len(indent), len(indent)
+	}
 
-	// writing of the first line which includes the colores prefix
-	// (colored prefix not included in second level lines)
-	builder.WriteString(item.Raw[left : right+ansiSeqLen]) // special case where we can right more than the ttyWidth since ansi color sequences are not printed to the terminal as chars
-	builder.WriteString("\n")
+	segments(data, func(seg segment) bool {
+		switch {
+		case seg.escape:
+			state = state.apply(seg.text)
+			builder.WriteString(seg.text)
+			return true
+		case seg.newline:
+			breakLine()
+			return true
+		}
 
-	if right+ttyWidth >= len(item.Raw)-ansiSeqLen {
-		builder.WriteString(indent)
-		builder.WriteString(item.Raw[right+ansiSeqLen:])
+		// at least one cluster is written per line
+		// even if the width is too small to fit it
+		if col+seg.width > ttyWidth && col > start {
+			breakLine()
+		}
+		builder.WriteString(seg.text)
+		col += seg.width
+		return true
+	})
 
-		return strings.Split(builder.String(), "\n")
+	if len(state) > 0 {
+		builder.WriteString(resetSequence)
 	}
+	return append(lines, builder.String())
+}
 
-	left += ttyWidth + ansiSeqLen
-	right += ttyWidth + ansiSeqLen
+// wrapASCII breaks the prefixed data of printable ASCII
+// characters which each take a column at byte offsets
+func wrapASCII(prefix string, data string, prefixWidth int, indent string, ttyWidth int) []string {
+	first := clamp1(ttyWidth - prefixWidth)
+	if first >= len(data) {
+		return []string{prefix + data}
+	}
 
-	for left < len(item.Raw) {
-		builder.WriteString(indent)
-		builder.WriteString(item.Raw[left : right-len(indent)])
-		builder.WriteString("\n")
-
-		left, right = left+ttyWidth-len(indent), right+ttyWidth-len(indent)
-		// last bits and bytes which are left over need to be
-		// written into the last line
-		if right >= len(item.Raw) {
-			builder.WriteString(indent)
-			builder.WriteString(item.Raw[left:])
-			break
+	room := clamp1(ttyWidth - len(indent))
+	lines := make([]string, 0, 2+(len(data)-first)/room)
+	lines = append(lines, prefix+data[:first])
+	for left := first; left < len(data); left += room {
+		right := left + room
+		if right > len(data) {
+			right = len(data)
 		}
+		lines = append(lines, indent+data[left:right])
 	}
+	return lines
+}
 
-	return strings.Split(builder.String(), "\n")
+func clamp1(a int) int {
+	if a < 1 {
+		return 1
+	}
+	return a
 }
@@ -1,15 +1,15 @@
 package store
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 
-	"github.com/KonstantinGasser/scotty/store/ring"
 	"github.com/charmbracelet/lipgloss"
 )
 
 var (
-	prefix  = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render("hello-world")
-	divider = " | "
+	prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render("hello-world") + divider
 )
 
 const (
@@ -19,27 +19,18 @@ const (
 	buggyString = `{"level":"error","ts":1692212915.723973,"caller":"application/structred.go:68","msg":"unable to do X","index":52,"error":"unable to do X","ts":1692212915.723955,"stacktrace":"main.handleLog\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:68\nmain.main\n\t/Users/konstantingasser/coffecode/scotty/application/structred.go:47\nruntime.main\n\t/usr/local/go/src/runtime/proc.go:250"}`
 )
 
-func makeItem(body string) ring.Item {
-	return ring.Item{
-		Label:       "hello-world",
-		Raw:         prefix + divider + body,
-		DataPointer: len(prefix) + len(divider),
-	}
-
-}
-
 func TestLineWrap(t *testing.T) {
 
 	tt := []struct {
 		name     string
 		ttyWidth int
-		item     ring.Item
+		body     string
 		want     []string
 	}{
 		{
 			name:     "random test logs",
 			ttyWidth: 176,
-			item:     makeItem(buggyString),
+			body:     buggyString,
 			want: []string{
 				`hello-world | {"level":"error","ts":1692212915.723973,"caller":"application/structred.go:68","msg":"unable to do X","index":52,"error":"unable to do X","ts":1692212915.723955,"`,
 				`            | stacktrace":"main.handleLog\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:68\nmain.main\n\t/Users/konstantingasser/coffecode/scotty/ap`,
@@ -49,17 +40,18 @@ func TestLineWrap(t *testing.T) {
 		{
 			name:     "short log line",
 			ttyWidth: 45,
-			item:     makeItem(bodyShort),
+			body:     bodyShort,
 			want: []string{
 				`hello-world | time="2023-08-16T19:06:36+02:00`,
 				`            | " level=error msg="msg=unable t`,
-				`            | o do X, error=unable to do X, index=7"`,
+				`            | o do X, error=unable to do X, i`,
+				`            | ndex=7"`,
 			},
 		},
 		{
 			name:     "medium log line",
 			ttyWidth: 65,
-			item:     makeItem(bodyMedium),
+			body:     bodyMedium,
 			want: []string{
 				`hello-world | {"level":"error","ts":1692205600.785263,"caller":"a`,
 				`            | pplication/structred.go:68","msg":"unable to do X",`,
@@ -68,13 +60,14 @@ func TestLineWrap(t *testing.T) {
 				`            | tantingasser/coffecode/scotty/test/application/stru`,
 				`            | ctred.go:68\nmain.main\n\t/Users/konstantingasser/c`,
 				`            | offecode/scotty/test/application/structred.go:47\nr`,
-				`            | untime.main\n\t/usr/local/go/src/runtime/proc.go:250"}`,
+				`            | untime.main\n\t/usr/local/go/src/runtime/proc.go:25`,
+				`            | 0"}`,
 			},
 		},
 		{
 			name:     "long log line",
 			ttyWidth: 100,
-			item:     makeItem(bodyLong),
+			body:     bodyLong,
 			want: []string{
 				`hello-world | {"insertId":"42","jsonPayload":{"message":"There was an error in the application","tim`,
 				`            | es":"2019-10-12T07:20:50.52Z"},"httpRequest":{"requestMethod":"GET"},"resource":{"type`,
@@ -89,10 +82,60 @@ func TestLineWrap(t *testing.T) {
 				`            | 7:42.411414059Z","spanId":"000000000000004a"}`,
 			},
 		},
+		{
+			name:     "wide runes",
+			ttyWidth: 20,
+			body:     "日本語のログです",
+			want: []string{
+				"hello-world | 日本語",
+				"            | のログ",
+				"            | です",
+			},
+		},
+		{
+			name:     "emoji",
+			ttyWidth: 20,
+			body:     "ok 👩‍💻 🇩🇪 done",
+			want: []string{
+				"hello-world | ok 👩‍💻 ",
+				"            | 🇩🇪 don",
+				"            | e",
+			},
+		},
+		{
+			name:     "combining marks",
+			ttyWidth: 20,
+			body:     "cafe\u0301 cafe\u0301",
+			want: []string{
+				"hello-world | cafe\u0301 c",
+				"            | afe\u0301",
+			},
+		},
+		{
+			name:     "colors continue on the next line",
+			ttyWidth: 20,
+			body:     "\x1b[31mred red red\x1b[0m ok",
+			want: []string{
+				"hello-world | \x1b[31mred re\x1b[0m",
+				"            | \x1b[31md red\x1b[0m ",
+				"            | ok",
+			},
+		},
+		{
+			name:     "newlines and tabs",
+			ttyWidth: 20,
+			body:     "panic:\tboom\r\nmain.go",
+			want: []string{
+				"hello-world | panic:",
+				"            |  boom",
+				"            | main.g",
+				"            | o",
+			},
+		},
 	}
 
 	for _, tc := range tt {
-		lines := lineWrap(tc.item, tc.ttyWidth)
+		lines := lineWrap(prefix, tc.body, tc.ttyWidth)
 
 		if len(lines) != len(tc.want) {
 			t.Fatalf("[%s] number of lines do not match.\n\tWanted: %d\n\tGot: %d", tc.name, len(tc.want), len(lines))
@@ -100,13 +143,70 @@ func TestLineWrap(t *testing.T) {
 
 		for i, line := range lines {
 			if line != tc.want[i] {
-				t.Fatalf("[%s] lines do not match.\n\tWanted: %s\n\tGot: %s", tc.name, tc.want[i], line)
+				t.Fatalf("[%s] lines do not match.\n\tWanted: %q\n\tGot: %q", tc.name, tc.want[i], line)
 			}
 		}
 
 	}
 }
 
+// FuzzLineWrap checks that wrapped lines never exceed the width,
+// keep valid UTF-8 and lose no printable character of the data.
+// The cases of TestLineWrap are the seed corpus.
+func FuzzLineWrap(f *testing.F) {
+
+	for _, body := range []string{bodyShort, bodyMedium, bodyLong, buggyString} {
+		f.Add(body, 45)
+		f.Add(body, 100)
+	}
+	f.Add("日本語のログです", 20)
+	f.Add("ok 👩‍💻 🇩🇪 done ☀️", 17)
+	f.Add("cafe\u0301 \x1b[1;31mred\x1b[0m \x1b]8;;https://scotty\x1b\\link\x1b]8;;\x1b\\", 21)
+	f.Add("panic:\tboom\r\nmain.go:12\x1b[", 16)
+
+	f.Fuzz(func(t *testing.T, body string, ttyWidth int) {
+		indent := len("hello-world | ")
+		// leave room for at least a wide rune per line
+		if ttyWidth < indent+2 || ttyWidth > 400 {
+			t.Skip()
+		}
+
+		lines := lineWrap(prefix, body, ttyWidth)
+
+		var got strings.Builder
+		for i, line := range lines {
+			if width := displayWidth(line); width > ttyWidth {
+				t.Fatalf("line %d is %d wide (tty width %d): %q", i, width, ttyWidth, line)
+			}
+			if utf8.ValidString(body) && !utf8.ValidString(line) {
+				t.Fatalf("line %d is not valid UTF-8: %q", i, line)
+			}
+			if i == 0 {
+				line = strings.TrimPrefix(line, prefix)
+			} else {
+				line = line[indent:]
+			}
+			got.WriteString(printable(line))
+		}
+		if want := printable(body); got.String() != want {
+			t.Fatalf("wanted the printable data:\n%q\ngot:\n%q", want, got.String())
+		}
+	})
+}
+
+// printable returns the text of s without escape sequences
+// and newlines as shown by the terminal
+func printable(s string) string {
+	var text strings.Builder
+	segments(s, func(seg segment) bool {
+		if !seg.escape {
+			text.WriteString(seg.text)
+		}
+		return true
+	})
+	return text.String()
+}
+
 // Current benchmark results:
 //
 // goos: darwin
@@ -116,12 +216,12 @@ func TestLineWrap(t *testing.T) {
 func BenchmarkLineWrapShort(b *testing.B) {
 
 	ttyWidth := 45
-	item := makeItem(bodyShort)
+	body := bodyShort
 
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		_ = lineWrap(item, ttyWidth)
+		_ = lineWrap(prefix, body, ttyWidth)
 	}
 }
 
@@ -134,12 +234,12 @@ func BenchmarkLineWrapShort(b *testing.B) {
 func BenchmarkLineWrapMedium(b *testing.B) {
 
 	ttyWidth := 65
-	item := makeItem(bodyMedium)
+	body := bodyMedium
 
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		_ = lineWrap(item, ttyWidth)
+		_ = lineWrap(prefix, body, ttyWidth)
 	}
 }
 
@@ -152,11 +252,11 @@ func BenchmarkLineWrapMedium(b *testing.B) {
 func BenchmarkLineWrapLong(b *testing.B) {
 
 	ttyWidth := 100
-	item := makeItem(bodyLong)
+	body := bodyLong
 
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		_ = lineWrap(item, ttyWidth)
+		_ = lineWrap(prefix, body, ttyWidth)
 	}
 }
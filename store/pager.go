@@ -8,6 +8,10 @@ import (
 	"github.com/KonstantinGasser/scotty/store/ring"
 )
 
+// noItem marks a line of the pager
+// which does not show any item
+const noItem = -1
+
 type Pager struct {
 	// if enabled the bufferView is
 	// not updated for each received message
@@ -15,6 +19,17 @@ type Pager struct {
 	// reader includes all required APIs
 	// to perform read operations on the ringbuffer
 	reader ring.Reader
+	// prefix renders the line prefix of each item
+	prefix *prefixer
+	// filter hides the items of muted beams
+	filter *filter
+	// bookmarks of the store
+	bookmarks bookmarks
+	// only is the label of the beam the
+	// pager is restricted to (if set)
+	only string
+	// query selects the logs shown by the pager
+	query Query
 	// bufferView is the build string to display.
 	// Its a representation of the buffered items
 	// concatinated by a newline.
@@ -28,6 +43,28 @@ type Pager struct {
 	// visisble within the page - and is tight to the
 	// provided size
 	buffer []string
+	// offsets holds the offset of the item each line
+	// of the buffer belongs to (noItem for empty lines)
+	offsets []int
+	// viewOffsets are the offsets of the lines
+	// of the bufferView
+	viewOffsets []int
+	// top is the offset of the first item shown
+	// after a Jump while the pager is paused
+	top uint32
+	// table renders one row per item with the values of
+	// the chosen columns instead of the wrapped lines
+	table *table
+	// sorted holds the offsets of all buffered items in
+	// the order of the sorted column while the paused page
+	// is sorted; sortTop is the first shown
+	sorted  []uint32
+	sortTop int
+	// nowrap cuts lines at the width of the page
+	// instead of wrapping them; column is the first
+	// column of the data shown
+	nowrap bool
+	column int
 	// Mainly used to determin string break-points
 	ttyWidth int
 	// position is it pagers pointer to an index in the
@@ -50,6 +87,12 @@ type Pager struct {
 	// configured refresh time has been reached in order
 	// to allow to minimize the cost of re-building
 	ticker *time.Ticker
+	// stale is true if logs on the page changed in
+	// place; the page is rebuilt once on the next render
+	stale bool
+	// shown counts the logs added to the
+	// page while moving the position
+	shown uint64
 }
 
 // MovePosition moves the buffers viewing position
@@ -60,27 +103,47 @@ type Pager struct {
 // The buffer's view is not changed and must be called indendenly
 func (pager *Pager) MovePosition() {
 
-	next := pager.reader.At(pager.position)
+	offset := pager.position
+	next := pager.reader.At(offset)
+	// the message did not add a log as it repeated a
+	// previous log (see Store.Insert). Its repeat counter
+	// is shown once the page is rebuilt by the next render
+	// such that a flood of repeats rebuilds it only once.
+	if next.Index() != offset+1 {
+		pager.stale = true
+		return
+	}
 	pager.position += 1
 
-	lines := lineWrap(next, pager.ttyWidth)
+	if pager.skips(next) {
+		return
+	}
+	pager.shown += 1
 
-	pager.shiftAppend(lines)
+	pager.shiftAppend(int(offset), pager.lines(next))
 }
 
-// shiftAppend takes the given lines and updates the pager's
-// buffer such that the lines are append to the buffer and if
-// nessecarry truncates the buffer.
+// Shown returns the number of logs added to the page by
+// MovePosition. Hidden and filtered logs as well as repeats
+// of a previous log are not counted.
+func (pager *Pager) Shown() uint64 {
+	return pager.shown
+}
+
+// shiftAppend takes the given lines of the item at the offset and
+// updates the pager's buffer such that the lines are append to the
+// buffer and if nessecarry truncates the buffer.
 //
 // The shift value depends on the len(lines) and each item of
 // the buffer is shifted N to the left leaving N free slots
 // at the end of the buffer for the N new lines.
-func (pager *Pager) shiftAppend(lines []string) {
+func (pager *Pager) shiftAppend(offset int, lines []string) {
 
 	var lineIndex int
 	if pager.writeHead < cap(pager.buffer) {
 		for pager.writeHead < cap(pager.buffer) && lineIndex < len(lines) {
 			pager.buffer[pager.writeHead] = lines[lineIndex]
+			pager.offsets[pager.writeHead] = offset
 
 			pager.writeHead += 1
 			lineIndex += 1
@@ -99,17 +162,32 @@ func (pager *Pager) shiftAppend(lines []string) {
 	// shifting the buffer to the left by N.
 	for i := overflow; i < cap(pager.buffer); i++ {
 		pager.buffer[i-overflow] = pager.buffer[i]
+		pager.offsets[i-overflow] = pager.offsets[i]
 	}
 
 	shiftOffset := (cap(pager.buffer)) - overflow
 
 	for i, j := shiftOffset, lineIndex; i < cap(pager.buffer); i, j = i+1, j+1 {
 		pager.buffer[i] = lines[j]
+		pager.offsets[i] = offset
 	}
 }
 
-func (pager *Pager) PauseRender()  { pager.paused = true }
-func (pager *Pager) ResumeRender() { pager.paused = false }
+// ResumeRender shows the latest logs again
+// (in arrival order if the page was sorted)
+func (pager *Pager) ResumeRender() {
+	pager.paused = false
+	pager.sorted = nil
+}
+
+// PauseRender freezes the current page while the pager
+// keeps tailing in the background
+func (pager *Pager) PauseRender() {
+	pager.paused = true
+	if first, ok := pager.first(); ok {
+		pager.top = first
+	}
+}
 
 // String returns a finshed formatted string representing
 // the current state of the pager.
@@ -119,13 +197,13 @@ func (pager *Pager) String() string {
 	}
 
 	if pager.ticker == nil {
-		pager.bufferView = strings.Join(pager.buffer, "\n")
+		pager.render()
 		return pager.bufferView
 	}
 
 	select {
 	case <-pager.ticker.C:
-		pager.bufferView = strings.Join(pager.buffer, "\n")
+		pager.render()
 		return pager.bufferView
 	default:
 		return pager.bufferView
@@ -136,24 +214,208 @@ func (pager *Pager) Resize(width int, height int) {
 
 	pager.ttyWidth = width
 	pager.size = uint8(height)
-
-	start := clamp(int(pager.position) - int(pager.size))
-
-	items := make([]ring.Item, pager.size)
-	pager.reader.OffsetRead(start, items)
+	pager.stale = false
 
 	buf := make([]string, pager.size)
+	offsets := make([]int, pager.size)
 	for i := range buf {
 		buf[i] = "\000"
+		offsets[i] = noItem
 	}
+	pager.buffer = buf
+	pager.offsets = offsets
 	pager.writeHead = 0
 
-	for _, item := range items {
-		lines := lineWrap(item, pager.ttyWidth)
-		pager.shiftAppend(lines)
+	for _, offset := range pager.latest() {
+		item := pager.reader.At(offset)
+		pager.shiftAppend(int(offset), pager.lines(item))
 	}
 }
 
+// latest returns the offsets of the latest items before the
+// pager's position which are shown (not evicted nor hidden)
+// in chronological order. At most one item per line is returned.
+func (pager *Pager) latest() []uint32 {
+
+	oldest, _, ok := pager.reader.Window()
+	if !ok || pager.position <= oldest {
+		return nil
+	}
+
+	var offsets []uint32
+	for offset := pager.position; offset > oldest && len(offsets) < int(pager.size); offset-- {
+		item := pager.reader.At(offset - 1)
+		if item.Evicted || pager.skips(item) {
+			continue
+		}
+		offsets = append(offsets, offset-1)
+	}
+
+	for i, j := 0, len(offsets)-1; i < j; i, j = i+1, j-1 {
+		offsets[i], offsets[j] = offsets[j], offsets[i]
+	}
+	return offsets
+}
+
+// Jump pauses the pager and shows the page starting at
+// the target. The pager keeps tailing in the background
+// and shows the latest logs again once resumed.
+func (pager *Pager) Jump(target Target) error {
+
+	offset, err := target.resolve(pager.reader)
+	if err != nil {
+		return err
+	}
+
+	items := make([]ring.Item, pager.size)
+	pager.reader.OffsetRead(int(offset), items)
+
+	var lines = make([]string, 0, pager.size)
+	var offsets = make([]int, 0, pager.size)
+	for i, item := range items {
+		// reached the latest item; OffsetRead
+		// continues with the oldest items
+		if item.Index() <= offset {
+			break
+		}
+		if item.Evicted || pager.skips(item) {
+			continue
+		}
+		wrapped := pager.lines(item)
+		lines = append(lines, wrapped...)
+		for range wrapped {
+			offsets = append(offsets, int(offset)+i)
+		}
+		if len(lines) >= int(pager.size) {
+			break
+		}
+	}
+	if len(lines) > int(pager.size) {
+		lines = lines[:pager.size]
+		offsets = offsets[:pager.size]
+	}
+
+	pager.paused = true
+	pager.top = offset
+	if pager.table != nil {
+		pager.renderTable(offsets, true)
+		return nil
+	}
+	pager.bufferView = strings.Join(lines, "\n")
+	pager.viewOffsets = offsets
+	return nil
+}
+
+// Scroll pauses the pager and shows the page delta items
+// after the first item currently shown (before if delta
+// is negative). Scrolling stops at the oldest and the
+// latest item of the buffer.
+func (pager *Pager) Scroll(delta int) error {
+
+	oldest, latest, ok := pager.reader.Window()
+	if !ok {
+		return nil
+	}
+
+	if pager.sorted != nil {
+		pager.sortTop += delta
+		if pager.sortTop > len(pager.sorted)-1 {
+			pager.sortTop = len(pager.sorted) - 1
+		}
+		if pager.sortTop < 0 {
+			pager.sortTop = 0
+		}
+		pager.renderSorted()
+		return nil
+	}
+
+	top := pager.top
+	if !pager.paused {
+		// nothing to scroll to after
+		// the latest logs
+		if delta > 0 {
+			return nil
+		}
+		top = oldest
+		if first, ok := pager.first(); ok {
+			top = first
+		}
+	}
+
+	target := int(top) + delta
+	if target < int(oldest) {
+		target = int(oldest)
+	}
+	if target > int(latest) {
+		target = int(latest)
+	}
+	return pager.Jump(TargetIndex(uint32(target)))
+}
+
+// At returns the offset of the item shown
+// in the row of the current page
+func (pager *Pager) At(row int) (uint32, bool) {
+	if row < 0 || row >= len(pager.viewOffsets) || pager.viewOffsets[row] == noItem {
+		return 0, false
+	}
+	return uint32(pager.viewOffsets[row]), true
+}
+
+// first returns the offset of the first
+// item shown on the current page
+func (pager *Pager) first() (uint32, bool) {
+	for row := range pager.viewOffsets {
+		if offset, ok := pager.At(row); ok {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// Selection returns the data of the items shown in the rows
+// from to the row to (both included) of the current page.
+// Items broken into multiple lines are returned once.
+func (pager *Pager) Selection(from int, to int) []string {
+	if from > to {
+		from, to = to, from
+	}
+
+	var selected []string
+	var last = noItem
+	for row := from; row <= to; row++ {
+		offset, ok := pager.At(row)
+		if !ok || int(offset) == last {
+			continue
+		}
+		last = int(offset)
+		selected = append(selected, pager.reader.At(offset).Raw)
+	}
+	return selected
+}
+
+// ToggleTime cycles through the time modes of the line
+// prefix and rebuilds the current page
+func (pager *Pager) ToggleTime() {
+	pager.prefix.toggleTime()
+	pager.Rebuild()
+	pager.Refresh()
+}
+
+// ToggleIndex shows or hides the index gutter
+// and rebuilds the current page
+func (pager *Pager) ToggleIndex() {
+	pager.prefix.index = !pager.prefix.index
+	pager.Rebuild()
+	pager.Refresh()
+}
+
+// Rebuild re-reads the items of the current page from
+// the ring.Buffer. Required if stored items have changed
+// in place.
+func (pager *Pager) Rebuild() {
+	pager.Resize(pager.ttyWidth, int(pager.size))
+}
+
 // // Rerender updates the pagers internal view which depends on
 // // the current tty width and height.
 // //
@@ -173,7 +435,7 @@ func (pager *Pager) reload(items ring.Slice) {
 		if len(item.Raw) <= 0 {
 			continue
 		}
-		lines := lineWrap(item, pager.ttyWidth)
+		lines := lineWrap(pager.prefix.render(item), item.Display(), pager.ttyWidth)
 
 		if int(written)+len(lines) <= int(pager.size) {
 			for _, line := range lines {
@@ -187,21 +449,30 @@ func (pager *Pager) reload(items ring.Slice) {
 	}
 }
 
+// Reset sizes the pager and shows the latest
+// logs before the pager's position
 func (pager *Pager) Reset(width int, height uint8) {
-	pager.ttyWidth = width
-	pager.size = height
-
-	pager.buffer = make([]string, pager.size)
-	for i := range pager.buffer {
-		pager.buffer[i] = "\000"
-	}
-	pager.bufferView = strings.Join(pager.buffer, "\n")
+	pager.Resize(width, int(height))
+	pager.render()
 }
 
 // Refresh disregards the time.Ticker and updates
 // the pager's view immediately
 func (pager *Pager) Refresh() {
+	pager.render()
+}
+
+// render builds the bufferView from the buffer
+func (pager *Pager) render() {
+	if pager.stale {
+		pager.Rebuild()
+	}
+	if pager.table != nil {
+		pager.renderTable(pager.offsets, false)
+		return
+	}
 	pager.bufferView = strings.Join(pager.buffer, "\n")
+	pager.viewOffsets = append(pager.viewOffsets[:0], pager.offsets...)
 }
 
 func (pager *Pager) debug() string {
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -75,7 +76,6 @@ func TestMoveDownAssertHeight(t *testing.T) {
 	var store *Store
 	var pager Pager
 
-	prefix := "test-label | "
 	tt := []struct {
 		name      string
 		maxWidth  int
@@ -88,15 +88,15 @@ func TestMoveDownAssertHeight(t *testing.T) {
 			maxHeight: 9,
 			maxWidth:  35,
 			sequence: []string{
-				"test-label | Line-1",
-				"test-label | Line-2",
-				"test-label | Line-3",
-				"test-label | Line-4",
-				"test-label | Line-5",
-				"test-label | Line-6",
-				"test-label | Line-7",
-				"test-label | Line-8",
-				"test-label | Line-9",
+				"Line-1",
+				"Line-2",
+				"Line-3",
+				"Line-4",
+				"Line-5",
+				"Line-6",
+				"Line-7",
+				"Line-8",
+				"Line-9",
 			},
 			checksum: []string{
 				"test-label | Line-1",
@@ -115,23 +115,23 @@ func TestMoveDownAssertHeight(t *testing.T) {
 			maxHeight: 9,
 			maxWidth:  35,
 			sequence: []string{
-				"test-label | Line-1",
-				"test-label | Line-2",
-				"test-label | Line-3",
-				"test-label | Line-4",
-				"test-label | Line-5",
-				"test-label | Line-6",
-				"test-label | Line-7",
-				"test-label | Line-8",
-				"test-label | Line-9",
-				"test-label | Line-10",
-				"test-label | Line-11",
-				"test-label | Line-12",
-				"test-label | Line-13",
-				"test-label | Line-14",
-				"test-label | Line-15",
-				"test-label | Line-16",
-				"test-label | Line-17",
+				"Line-1",
+				"Line-2",
+				"Line-3",
+				"Line-4",
+				"Line-5",
+				"Line-6",
+				"Line-7",
+				"Line-8",
+				"Line-9",
+				"Line-10",
+				"Line-11",
+				"Line-12",
+				"Line-13",
+				"Line-14",
+				"Line-15",
+				"Line-16",
+				"Line-17",
 			},
 			checksum: []string{
 				"test-label | Line-9",
@@ -150,15 +150,15 @@ func TestMoveDownAssertHeight(t *testing.T) {
 			maxHeight: 9,
 			maxWidth:  18,
 			sequence: []string{
-				"test-label | Line-10",
-				"test-label | Line-20",
-				"test-label | Line-30",
-				"test-label | Line-40",
-				"test-label | Line-50",
-				"test-label | Line-60",
-				"test-label | Line-70",
-				"test-label | Line-80",
-				"test-label | Line-90",
+				"Line-10",
+				"Line-20",
+				"Line-30",
+				"Line-40",
+				"Line-50",
+				"Line-60",
+				"Line-70",
+				"Line-80",
+				"Line-90",
 			},
 			checksum: []string{
 				"           | 50",
@@ -179,7 +179,7 @@ func TestMoveDownAssertHeight(t *testing.T) {
 		pager = store.NewPager(uint8(height), tc.maxWidth, testRefreshRate)
 
 		for _, seq := range tc.sequence {
-			store.Insert("test-label", len(prefix), []byte(seq))
+			store.Insert("test-label", time.Now(), []byte(seq))
 			pager.MovePosition()
 		}
 
@@ -206,9 +206,8 @@ func TestAssertSameCapacity(t *testing.T) {
 	store := New(1024)
 	pager := store.NewPager(uint8(capacity), 100, testRefreshRate)
 
-	prefix := "test-label | "
 	for i := 0; i < 2048; i++ {
-		store.Insert("test-label", len(prefix), []byte(`{"level":"error","ts":1692292122.983928,"caller":"application/structred.go:68","msg":"unable to do X","index":81,"error":"unable to do X","ts":1692292122.9839098,"stacktrace":"main.handleLog\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:68\nmain.main\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:47\nruntime.main\n\t/usr/local/go/src/runtime/proc.go:250"}`))
+		store.Insert("test-label", time.Now(), []byte(`{"level":"error","ts":1692292122.983928,"caller":"application/structred.go:68","msg":"unable to do X","index":81,"error":"unable to do X","ts":1692292122.9839098,"stacktrace":"main.handleLog\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:68\nmain.main\n\t/Users/konstantingasser/coffecode/scotty/test/application/structred.go:47\nruntime.main\n\t/usr/local/go/src/runtime/proc.go:250"}`))
 		pager.MovePosition()
 		if cap(pager.buffer) != capacity {
 			t.Fatalf("Capacity has changed after updating the buffer. From: %d -> To: %d", capacity, cap(pager.buffer))
@@ -217,6 +216,98 @@ func TestAssertSameCapacity(t *testing.T) {
 
 }
 
+func TestMuteHidesBeam(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(4, 35, testRefreshRate)
+
+	for i, label := range []string{"a", "b", "a", "b"} {
+		store.Insert(label, time.Now(), []byte(fmt.Sprintf("Line-%d", i+1)))
+		pager.MovePosition()
+	}
+
+	store.Mute("b")
+	pager.Rebuild()
+
+	want := "a | Line-1\na | Line-3\n\x00\n\x00"
+	if got := pager.String(); got != want {
+		t.Fatalf("wanted muted beam to be hidden:\n%q\ngot:\n%q", want, got)
+	}
+
+	store.Insert("b", time.Now(), []byte("Line-5"))
+	pager.MovePosition()
+	if got := pager.String(); got != want {
+		t.Fatalf("wanted logs of muted beam to be skipped:\n%q\ngot:\n%q", want, got)
+	}
+
+	store.Solo("b")
+	pager.Rebuild()
+
+	want = "b | Line-2\nb | Line-4\nb | Line-5\n\x00"
+	if got := pager.String(); got != want {
+		t.Fatalf("wanted only the solo beam:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestOnlyBeam(t *testing.T) {
+
+	store := New(12)
+	all := store.NewPager(4, 35, testRefreshRate)
+	beam := store.NewPager(4, 35, testRefreshRate)
+	beam.Only("b")
+
+	for i, label := range []string{"a", "b", "a", "b"} {
+		store.Insert(label, time.Now(), []byte(fmt.Sprintf("Line-%d", i+1)))
+		all.MovePosition()
+		beam.MovePosition()
+	}
+
+	want := "b | Line-2\nb | Line-4\n\x00\n\x00"
+	if got := beam.String(); got != want {
+		t.Fatalf("wanted only the logs of the beam:\n%q\ngot:\n%q", want, got)
+	}
+	if got := all.String(); !strings.HasPrefix(got, "a | Line-1\nb | Line-2") {
+		t.Fatalf("wanted other pagers to show all beams, got:\n%q", got)
+	}
+}
+
+func TestScrollAndSelect(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(3, 35, testRefreshRate)
+
+	for i := 0; i < 8; i++ {
+		store.Insert("test-label", time.Now(), []byte(fmt.Sprintf("Line-%d", i)))
+		pager.MovePosition()
+	}
+	pager.Refresh()
+
+	if offset, ok := pager.At(0); !ok || offset != 5 {
+		t.Fatalf("wanted first row to show offset 5, got: %d (ok: %t)", offset, ok)
+	}
+
+	if err := pager.Scroll(-3); err != nil {
+		t.Fatalf("unable to scroll: %v", err)
+	}
+	want := "test-label | Line-2\ntest-label | Line-3\ntest-label | Line-4"
+	if got := pager.String(); got != want {
+		t.Fatalf("wanted page after scrolling up:\n%q\ngot:\n%q", want, got)
+	}
+
+	// scrolling stops at the oldest item
+	if err := pager.Scroll(-10); err != nil {
+		t.Fatalf("unable to scroll: %v", err)
+	}
+	if offset, _ := pager.At(0); offset != 0 {
+		t.Fatalf("wanted scrolling to stop at offset 0, got: %d", offset)
+	}
+
+	selected := pager.Selection(2, 1)
+	if strings.Join(selected, ",") != "Line-1,Line-2" {
+		t.Fatalf("wanted rows 1-2 to be selected, got: %v", selected)
+	}
+}
+
 // Current benchmark results:
 //
 // goos: darwin
@@ -229,7 +320,7 @@ func BenchmarkMovePosition(b *testing.B) {
 
 	// fill ring buffer until full so pager.position always is a hit
 	for i := 0; i < 2048; i++ {
-		store.Insert("dummy", len("dummy"), []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
+		store.Insert("dummy", time.Now(), []byte(`{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`))
 	}
 
 	b.ResetTimer()
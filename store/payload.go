@@ -0,0 +1,530 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// PayloadFormat pretty prints the data of a log
+// shown in the modal of the browse view
+type PayloadFormat struct {
+	// Name under which a beam can be
+	// forced to be shown in the format
+	Name string
+	// Detect reports whether the data is in the format
+	Detect func(data string) bool
+	// Format pretty prints the data
+	Format func(data string) (string, error)
+}
+
+// payloadFormats are tried in order to
+// detect the format of a log's data
+var payloadFormats = []PayloadFormat{
+	{Name: "json", Detect: isJSON, Format: formatJSON},
+	{Name: "xml", Detect: isXML, Format: formatXML},
+	{Name: "url", Detect: isURLQuery, Format: formatURLQuery},
+	{Name: "go", Detect: isGoStruct, Format: formatGoStruct},
+	{Name: "yaml", Detect: isYAML, Format: formatYAML},
+	{Name: "kv", Detect: isKeyValue, Format: formatKeyValue},
+	{Name: "logfmt", Detect: isLogfmt, Format: formatLogfmt},
+	{Name: "prototext", Detect: isProtoText, Format: formatProtoText},
+}
+
+// RegisterPayloadFormat adds the format to the formats which
+// are detected. Registered formats are tried before the built-in
+// ones and replace any format of the same name.
+func RegisterPayloadFormat(format PayloadFormat) {
+	formats := []PayloadFormat{format}
+	for _, f := range payloadFormats {
+		if f.Name != format.Name {
+			formats = append(formats, f)
+		}
+	}
+	payloadFormats = formats
+}
+
+// PayloadFormats returns the names of all formats
+func PayloadFormats() []string {
+	names := make([]string, len(payloadFormats))
+	for i, f := range payloadFormats {
+		names[i] = f.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupPayloadFormat(name string) (PayloadFormat, bool) {
+	for _, f := range payloadFormats {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return PayloadFormat{}, false
+}
+
+// FormatPayloadAs shows the logs of the beam in the format
+// instead of detecting the format of each log. An empty name
+// detects the format again.
+func (store *Store) FormatPayloadAs(label string, name string) error {
+	if name == "" {
+		delete(store.payloads, label)
+		return nil
+	}
+	if _, ok := lookupPayloadFormat(name); !ok {
+		return fmt.Errorf("unknown format %q (use one of: %s)", name, strings.Join(PayloadFormats(), ", "))
+	}
+	store.payloads[label] = name
+	return nil
+}
+
+// formatPayload pretty prints the data in the forced format or
+// else in the first format detected. Data in no known format or
+// failing to be formatted is returned as is.
+func formatPayload(data string, forced string) (string, string) {
+	if f, ok := lookupPayloadFormat(forced); ok {
+		if pretty, err := f.Format(data); err == nil {
+			return pretty, f.Name
+		}
+		return data, ""
+	}
+
+	for _, f := range payloadFormats {
+		if !f.Detect(data) {
+			continue
+		}
+		if pretty, err := f.Format(data); err == nil {
+			return pretty, f.Name
+		}
+	}
+	return data, ""
+}
+
+func isJSON(data string) bool {
+	data = strings.TrimSpace(data)
+	return (strings.HasPrefix(data, "{") || strings.HasPrefix(data, "[")) && json.Valid([]byte(data))
+}
+
+// formatJSON pretty prints the data expanding
+// strings which hold JSON themselves
+func formatJSON(data string) (string, error) {
+	var v interface{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+
+	pretty, err := jsonF.Marshal(expandJSON(v))
+	if err != nil {
+		return "", err
+	}
+	return string(pretty), nil
+}
+
+// expandJSON replaces strings holding a JSON
+// object or array by their decoded value
+func expandJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = expandJSON(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = expandJSON(value)
+		}
+	case string:
+		if !isJSON(v) {
+			return v
+		}
+		var embedded interface{}
+		decoder := json.NewDecoder(strings.NewReader(v))
+		decoder.UseNumber()
+		if err := decoder.Decode(&embedded); err != nil {
+			return v
+		}
+		return expandJSON(embedded)
+	}
+	return v
+}
+
+func isXML(data string) bool {
+	data = strings.TrimSpace(data)
+	return strings.HasPrefix(data, "<") && strings.HasSuffix(data, ">")
+}
+
+// formatXML indents the elements of the data
+func formatXML(data string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(data))
+
+	var b bytes.Buffer
+	encoder := xml.NewEncoder(&b)
+	encoder.Indent("", "  ")
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		// whitespace between elements is
+		// replaced by the indentation
+		if chars, ok := token.(xml.CharData); ok && len(bytes.TrimSpace(chars)) == 0 {
+			continue
+		}
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var urlQuery = regexp.MustCompile(`^[\w.\-\[\]%]+=[^&\s]*(&[\w.\-\[\]%]+=[^&\s]*)+$`)
+
+func isURLQuery(data string) bool {
+	return urlQuery.MatchString(strings.TrimPrefix(strings.TrimSpace(data), "?"))
+}
+
+// formatURLQuery lists the unescaped parameters of the
+// query in the order they appear in the data
+func formatURLQuery(data string) (string, error) {
+	var pairs [][2]string
+	for _, param := range strings.Split(strings.TrimPrefix(strings.TrimSpace(data), "?"), "&") {
+		key, value, _ := strings.Cut(param, "=")
+		k, err := url.QueryUnescape(key)
+		if err != nil {
+			return "", err
+		}
+		v, err := url.QueryUnescape(value)
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, [2]string{k, v})
+	}
+	return alignPairs(pairs), nil
+}
+
+// fields printed with %+v have no space after
+// the colon unlike a YAML flow mapping
+var goStruct = regexp.MustCompile(`^&?([\w.*]+)?\{\w+:\S`)
+
+func isGoStruct(data string) bool {
+	data = strings.TrimSpace(data)
+	return goStruct.MatchString(data) && strings.HasSuffix(data, "}") && balanced(data, '{', '}')
+}
+
+// formatGoStruct indents a struct printed with %+v
+// or %#v placing each field on its own line
+func formatGoStruct(data string) (string, error) {
+	var (
+		b      strings.Builder
+		depth  int
+		quoted bool
+	)
+	indent := func() {
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("  ", depth))
+	}
+
+	data = strings.TrimSpace(data)
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case quoted:
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				b.WriteByte(data[i])
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+			b.WriteByte(c)
+		case c == '{':
+			depth++
+			b.WriteByte(c)
+			indent()
+		case c == '}':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("unbalanced braces")
+			}
+			indent()
+			b.WriteByte(c)
+		// fields are separated by a space (%+v)
+		// or a comma and a space (%#v)
+		case c == ',' && depth > 0 && i+1 < len(data) && data[i+1] == ' ':
+			b.WriteByte(c)
+			i++
+			indent()
+		case c == ' ' && depth > 0 && startsField(data[i+1:]):
+			indent()
+		case c == ':' && depth > 0:
+			b.WriteString(": ")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+var goField = regexp.MustCompile(`^\w+:`)
+
+// startsField reports whether s starts
+// with the name of a struct field
+func startsField(s string) bool {
+	return goField.MatchString(s)
+}
+
+func isYAML(data string) bool {
+	data = strings.TrimSpace(data)
+	if !strings.HasPrefix(data, "{") && !strings.HasPrefix(data, "---") && !strings.Contains(data, "\n") {
+		return false
+	}
+	var m yaml.MapSlice
+	return yaml.Unmarshal([]byte(data), &m) == nil && len(m) > 0
+}
+
+// formatYAML prints a flow mapping ({a: 1, b: [x]})
+// or a YAML document in block style
+func formatYAML(data string) (string, error) {
+	var m yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(data), &m); err != nil {
+		return "", err
+	}
+	pretty, err := yaml.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(pretty), "\n"), nil
+}
+
+var (
+	keyValue    = regexp.MustCompile(`^\s*[\w.\-]+\s*=`)
+	keyValueSep = regexp.MustCompile(`\s*[,;]\s*`)
+)
+
+func isKeyValue(data string) bool {
+	parts := keyValueSep.Split(strings.TrimSpace(data), -1)
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if !keyValue.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatKeyValue lists the pairs of data
+// separated by a comma or semicolon
+func formatKeyValue(data string) (string, error) {
+	var pairs [][2]string
+	for _, part := range keyValueSep.Split(strings.TrimSpace(data), -1) {
+		key, value, _ := strings.Cut(part, "=")
+		pairs = append(pairs, [2]string{strings.TrimSpace(key), unquote(strings.TrimSpace(value))})
+	}
+	return alignPairs(pairs), nil
+}
+
+var logfmtPair = regexp.MustCompile(`([\w.\-]+)(?:=(?:"((?:[^"\\]|\\.)*)"|(\S*)))?`)
+
+func isLogfmt(data string) bool {
+	data = strings.TrimSpace(data)
+	if !strings.Contains(data, "=") {
+		return false
+	}
+	// the pairs must make up the whole data and
+	// most of them must have a value such that
+	// text with a single pair is not logfmt
+	rest := logfmtPair.ReplaceAllString(data, "")
+	var values, bare int
+	for _, match := range logfmtPair.FindAllString(data, -1) {
+		if strings.Contains(match, "=") {
+			values++
+		} else {
+			bare++
+		}
+	}
+	return strings.TrimSpace(rest) == "" && values > bare
+}
+
+// formatLogfmt lists the pairs of a
+// logfmt line (bare keys are kept)
+func formatLogfmt(data string) (string, error) {
+	var pairs [][2]string
+	for _, match := range logfmtPair.FindAllStringSubmatch(data, -1) {
+		value := match[3]
+		if match[2] != "" {
+			value = strings.ReplaceAll(match[2], `\"`, `"`)
+		}
+		pairs = append(pairs, [2]string{match[1], value})
+	}
+	return alignPairs(pairs), nil
+}
+
+var protoField = regexp.MustCompile(`^[\w.\[\]]+\s*(:|\{|<)`)
+
+func isProtoText(data string) bool {
+	data = strings.TrimSpace(data)
+	return protoField.MatchString(data) && balanced(data, '{', '}') && balanced(data, '<', '>')
+}
+
+// formatProtoText places each field of a message in the
+// protobuf text format on its own line indenting nested
+// messages
+func formatProtoText(data string) (string, error) {
+	tokens, err := protoTokens(data)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		lines []string
+		line  strings.Builder
+		depth int
+	)
+	flush := func() {
+		if line.Len() > 0 {
+			lines = append(lines, strings.Repeat("  ", depth)+line.String())
+			line.Reset()
+		}
+	}
+	for i, token := range tokens {
+		switch token {
+		case "{", "<":
+			line.WriteString(" " + token)
+			flush()
+			depth++
+		case "}", ">":
+			flush()
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("unbalanced message")
+			}
+			lines = append(lines, strings.Repeat("  ", depth)+token)
+		case ":":
+			line.WriteString(": ")
+		default:
+			if i > 0 && tokens[i-1] == ":" {
+				line.WriteString(token)
+				continue
+			}
+			// a name must be followed by a value or
+			// message else the data is plain text
+			if i+1 >= len(tokens) || !strings.Contains(":{<", tokens[i+1]) {
+				return "", fmt.Errorf("field %s has no value", token)
+			}
+			flush()
+			line.WriteString(token)
+		}
+	}
+	flush()
+	return strings.Join(lines, "\n"), nil
+}
+
+// protoTokens splits data into names, values (quoted
+// strings kept together) and the delimiters {}<>:
+func protoTokens(data string) ([]string, error) {
+	var (
+		tokens []string
+		token  strings.Builder
+	)
+	next := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '"', '\'':
+			next()
+			end := i + 1
+			for end < len(data) && data[end] != c {
+				if data[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(data) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, data[i:end+1])
+			i = end
+		case ' ', '\t', '\n', ',', ';':
+			next()
+		case '{', '}', '<', '>', ':':
+			next()
+			tokens = append(tokens, string(c))
+		default:
+			token.WriteByte(c)
+		}
+	}
+	next()
+	return tokens, nil
+}
+
+// alignPairs lists the pairs one per line with
+// the values aligned after the longest key
+func alignPairs(pairs [][2]string) string {
+	var width int
+	for _, pair := range pairs {
+		if len(pair[0]) > width {
+			width = len(pair[0])
+		}
+	}
+
+	lines := make([]string, len(pairs))
+	for i, pair := range pairs {
+		padding := strings.Repeat(" ", width-len(pair[0]))
+		lines[i] = jsonF.KeyColor.Sprint(pair[0]) + padding + " = " + pair[1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// balanced reports whether the open and close
+// characters of data (outside quotes) are balanced
+func balanced(data string, open, close byte) bool {
+	var depth int
+	var quoted bool
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == open && !quoted:
+			depth++
+		case c == close && !quoted:
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && !quoted
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatPayload(t *testing.T) {
+
+	tt := []struct {
+		name   string
+		data   string
+		format string
+		want   string
+	}{
+		{
+			name:   "json with embedded json",
+			data:   `{"error":"{\"code\":500,\"cause\":\"[1,2]\"}"}`,
+			format: "json",
+			want:   "{\n  \"error\": {\n    \"cause\": [\n      1,\n      2\n    ],\n    \"code\": 500\n  }\n}",
+		},
+		{
+			name:   "xml",
+			data:   `<order id="1"><item>tea</item></order>`,
+			format: "xml",
+			want:   "<order id=\"1\">\n  <item>tea</item>\n</order>",
+		},
+		{
+			name:   "url query",
+			data:   `?q=space%20ship&page=2`,
+			format: "url",
+			want:   "q    = space ship\npage = 2",
+		},
+		{
+			name:   "go struct",
+			data:   `&{Name:scotty Inner:{Port:8080 Tags:[a b]}}`,
+			format: "go",
+			want:   "&{\n  Name: scotty\n  Inner: {\n    Port: 8080\n    Tags: [a b]\n  }\n}",
+		},
+		{
+			name:   "yaml flow mapping",
+			data:   `{name: scotty, ports: [80, 443]}`,
+			format: "yaml",
+			want:   "name: scotty\nports:\n- 80\n- 443",
+		},
+		{
+			name:   "key value",
+			data:   `user=jane, role=admin; ok="yes"`,
+			format: "kv",
+			want:   "user = jane\nrole = admin\nok   = yes",
+		},
+		{
+			name:   "logfmt",
+			data:   `level=warn msg="slow request" latency=980 retried`,
+			format: "logfmt",
+			want:   "level   = warn\nmsg     = slow request\nlatency = 980\nretried = ",
+		},
+		{
+			name:   "protobuf text",
+			data:   `name: "scotty" port: 8080 tls { cert: "a.pem" }`,
+			format: "prototext",
+			want:   "name: \"scotty\"\nport: 8080\ntls {\n  cert: \"a.pem\"\n}",
+		},
+		{
+			name:   "plain text",
+			data:   `connection reset by peer`,
+			format: "",
+			want:   `connection reset by peer`,
+		},
+	}
+
+	for _, tc := range tt {
+		pretty, format := formatPayload(tc.data, "")
+		if format != tc.format {
+			t.Fatalf("[%s] wanted format %q, got: %q", tc.name, tc.format, format)
+		}
+		if pretty != tc.want {
+			t.Fatalf("[%s] wanted:\n%q\ngot:\n%q", tc.name, tc.want, pretty)
+		}
+	}
+}
+
+func TestFormatPayloadAs(t *testing.T) {
+
+	store := New(4)
+	if err := store.FormatPayloadAs("api", "unknown"); err == nil {
+		t.Fatalf("wanted an error for an unknown format")
+	}
+	if err := store.FormatPayloadAs("api", "logfmt"); err != nil {
+		t.Fatalf("unable to force the format: %v", err)
+	}
+
+	// detected as key=value unless forced
+	data := `user=jane,role=admin`
+	pretty, format := formatPayload(data, store.payloads["api"])
+	if format != "logfmt" || pretty != "user = jane,role=admin" {
+		t.Fatalf("wanted logfmt, got %s:\n%s", format, pretty)
+	}
+
+	RegisterPayloadFormat(PayloadFormat{
+		Name:   "upper",
+		Detect: func(data string) bool { return strings.HasPrefix(data, "!") },
+		Format: func(data string) (string, error) { return strings.ToUpper(data), nil },
+	})
+	defer func() { payloadFormats = payloadFormats[1:] }()
+
+	if pretty, format := formatPayload("!loud", ""); format != "upper" || pretty != "!LOUD" {
+		t.Fatalf("wanted the registered format to be detected, got %s: %s", format, pretty)
+	}
+}
+
+func TestFormatPayloadText(t *testing.T) {
+
+	for _, data := range []string{
+		`error: connection refused by peer`,
+		`retrying the request with retries=3 left`,
+	} {
+		if pretty, format := formatPayload(data, ""); pretty != data {
+			t.Fatalf("wanted %q as is, got it formatted as %s:\n%s", data, format, pretty)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/store/ring"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	divider = " | "
+)
+
+// TimeMode decides if and how the time of
+// a log is shown in the line prefix
+type TimeMode int
+
+const (
+	TimeOff TimeMode = iota
+	// TimeAbsolute shows the time of day of the log
+	TimeAbsolute
+	// TimeRelative shows the time passed since
+	// the previous log in the buffer
+	TimeRelative
+)
+
+const (
+	absoluteLayout = "15:04:05.000"
+	// lastSeenLayout is the time a
+	// repeated log was last received
+	lastSeenLayout = "15:04:05"
+	// indexWidth is the minimal width of the
+	// index gutter keeping the lines aligned
+	indexWidth = 6
+)
+
+var (
+	timeStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// ParseTimeMode maps the name of a TimeMode as
+// used in the config to its TimeMode
+func ParseTimeMode(name string) (TimeMode, error) {
+	switch name {
+	case "", "off":
+		return TimeOff, nil
+	case "absolute":
+		return TimeAbsolute, nil
+	case "relative":
+		return TimeRelative, nil
+	}
+	return TimeOff, fmt.Errorf("unknown time mode %q (options: off, absolute, relative)", name)
+}
+
+type highlight struct {
+	pattern *regexp.Regexp
+	divider string
+}
+
+// prefixer renders the line prefix (label + padding + divider)
+// of an item at view time. Since the prefix is not stored with
+// the item, changes to a label's color or the label width apply
+// to all items alike and keep the lines aligned.
+type prefixer struct {
+	// reader is used to look up the previous
+	// item for TimeRelative
+	reader   ring.Reader
+	timeMode TimeMode
+	// index shows the index of each log in
+	// front of the prefix (the gutter)
+	index  bool
+	colors map[string]lipgloss.Color
+	// width is the length of the longest label
+	// known to the prefixer
+	width      int
+	highlights []highlight
+	// bookmarks are marked in place of the divider
+	bookmarks bookmarks
+	// cache of the rendered label and padding for
+	// each label. Invalidated on any color or width change
+	cache map[string]string
+}
+
+func newPrefixer(reader ring.Reader) *prefixer {
+	return &prefixer{
+		reader: reader,
+		colors: make(map[string]lipgloss.Color),
+		cache:  make(map[string]string),
+	}
+}
+
+// register adds the label if not yet known and
+// adjusts the width if required
+func (p *prefixer) register(label string) {
+	if _, ok := p.colors[label]; ok {
+		return
+	}
+
+	p.colors[label] = lipgloss.Color("")
+	if len(label) > p.width {
+		p.width = len(label)
+		p.invalidate()
+	}
+}
+
+func (p *prefixer) setColor(label string, color lipgloss.Color) {
+	p.register(label)
+	p.colors[label] = color
+	delete(p.cache, label)
+}
+
+func (p *prefixer) invalidate() {
+	p.cache = make(map[string]string)
+}
+
+// render returns the finished prefix of the item. Empty
+// items have no prefix.
+func (p *prefixer) render(item ring.Item) string {
+	if len(item.Raw) <= 0 {
+		return ""
+	}
+	return p.gutter(item) + p.time(item) + p.label(item.Label) + p.divider(item) + p.repeats(item)
+}
+
+// repeats returns how often and when the log was last
+// received if it was received more than once in a row
+func (p *prefixer) repeats(item ring.Item) string {
+	if item.Repeats == 0 {
+		return ""
+	}
+	return timeStyle.Render(fmt.Sprintf("×%d last seen %s", item.Repeats+1, item.LastSeen.Format(lastSeenLayout))) + " "
+}
+
+// gutter returns the index of the item as typed into
+// the browse and jump prompts if the gutter is shown
+func (p *prefixer) gutter(item ring.Item) string {
+	if !p.index || item.Index() == 0 {
+		return ""
+	}
+	return timeStyle.Render(fmt.Sprintf("%*d", indexWidth, item.Index()-1)) + " "
+}
+
+// toggleTime cycles through the TimeModes
+func (p *prefixer) toggleTime() {
+	p.timeMode = (p.timeMode + 1) % (TimeRelative + 1)
+}
+
+func (p *prefixer) time(item ring.Item) string {
+	switch p.timeMode {
+	case TimeAbsolute:
+		return timeStyle.Render(item.Time().Format(absoluteLayout)) + " "
+	case TimeRelative:
+		var delta time.Duration
+		// Index is 1-based; the offset of the previous item
+		// is therefore Index-2. The previous item might already
+		// be overwritten in which case no delta is shown
+		if item.Index() > 1 {
+			prev := p.reader.At(item.Index() - 2)
+			if prev.Index() == item.Index()-1 {
+				delta = item.Time().Sub(prev.Time())
+			}
+		}
+		return timeStyle.Render(fmt.Sprintf("%+11.3fs", delta.Seconds())) + " "
+	}
+	return ""
+}
+
+func (p *prefixer) label(label string) string {
+	if cached, ok := p.cache[label]; ok {
+		return cached
+	}
+
+	rendered := label
+	if color := p.colors[label]; color != "" {
+		rendered = lipgloss.NewStyle().Foreground(color).Render(label)
+	}
+	rendered += strings.Repeat(" ", clamp(p.width-len(label)))
+
+	p.cache[label] = rendered
+	return rendered
+}
+
+// divider returns the divider between line prefix and data
+// which is colored if the data matches any highlight rule.
+// Bookmarked items show a bookmark instead.
+func (p *prefixer) divider(item ring.Item) string {
+	if item.Index() > 0 && p.bookmarks.bookmarked(item.Index()-1) {
+		return renderBookmarkDivider()
+	}
+	for _, hl := range p.highlights {
+		if hl.pattern.MatchString(item.Raw) {
+			return hl.divider
+		}
+	}
+	return divider
+}
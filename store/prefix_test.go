@@ -0,0 +1,62 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestPrefixAlignment(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(3, 50, testRefreshRate)
+
+	// the longer label connects after the first log has
+	// been received which must not break the alignment
+	store.Insert("api", time.Now(), []byte("first"))
+	pager.MovePosition()
+	store.Insert("payment-svc", time.Now(), []byte("second"))
+	pager.MovePosition()
+	store.Insert("api", time.Now(), []byte("third"))
+	pager.MovePosition()
+
+	pager.Rebuild()
+
+	want := []string{
+		"api         | first",
+		"payment-svc | second",
+		"api         | third",
+	}
+
+	for i, line := range pager.buffer {
+		if line != want[i] {
+			t.Fatalf("wanted line: %q - got: %q", want[i], line)
+		}
+	}
+}
+
+func TestPrefixIndex(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(2, 50, testRefreshRate)
+	store.ShowIndex(true)
+
+	for _, data := range []string{"first", "second"} {
+		store.Insert("api", time.Now(), []byte(data))
+		pager.MovePosition()
+	}
+	pager.Rebuild()
+
+	want := []string{
+		"     0 api | first",
+		"     1 api | second",
+	}
+
+	// the gutter is rendered faint
+	sgr := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	for i, line := range pager.buffer {
+		if line = sgr.ReplaceAllString(line, ""); line != want[i] {
+			t.Fatalf("wanted line: %q - got: %q", want[i], line)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/store/ring"
+)
+
+var (
+	// term of a query comparing a field with a value
+	fieldTerm = regexp.MustCompile(`^([\w.\-]+)(!=|>=|<=|=|>|<|~)(.*)$`)
+)
+
+// Query selects logs by terms which all must match. A term
+// either compares a field with a value
+//
+//	level=error   the field equals the value
+//	level!=debug  the field differs from the value (or is missing)
+//	latency>200   the field is a number greater than the value (>= < <=)
+//	msg~time.*out the field matches the regular expression
+//
+// or is a text the log contains (case insensitive). Values with
+// spaces are quoted: msg="slow request". Fields are read from
+// JSON logs (user.id) and key=value logs.
+type Query struct {
+	filter string
+	terms  []term
+}
+
+type term struct {
+	field, op, value string
+	number           float64
+	pattern          *regexp.Regexp
+}
+
+// ParseQuery parses the filter into a Query. An
+// empty filter matches all logs.
+func ParseQuery(filter string) (Query, error) {
+
+	words, err := splitQuoted(filter)
+	if err != nil {
+		return Query{}, err
+	}
+
+	query := Query{filter: strings.TrimSpace(filter)}
+	for _, word := range words {
+		match := fieldTerm.FindStringSubmatch(word)
+		if match == nil {
+			query.terms = append(query.terms, term{value: strings.ToLower(word)})
+			continue
+		}
+
+		t := term{field: match[1], op: match[2], value: match[3]}
+		switch t.op {
+		case ">", ">=", "<", "<=":
+			if t.number, err = strconv.ParseFloat(t.value, 64); err != nil {
+				return Query{}, fmt.Errorf("%s: %q is not a number", word, t.value)
+			}
+		case "~":
+			if t.pattern, err = regexp.Compile(t.value); err != nil {
+				return Query{}, fmt.Errorf("%s: %v", word, err)
+			}
+		}
+		query.terms = append(query.terms, t)
+	}
+	return query, nil
+}
+
+// String returns the filter the query is parsed from
+func (query Query) String() string {
+	return query.filter
+}
+
+// Empty reports whether the query matches all logs
+func (query Query) Empty() bool {
+	return len(query.terms) == 0
+}
+
+// Match reports whether the data of a log
+// matches all terms of the query
+func (query Query) Match(data string) bool {
+	for _, t := range query.terms {
+		if !t.match(data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t term) match(data string) bool {
+	if t.field == "" {
+		return strings.Contains(strings.ToLower(data), t.value)
+	}
+
+	value, ok := fieldValue(data, t.field)
+	switch t.op {
+	case "=":
+		return ok && value == t.value
+	case "!=":
+		return !ok || value != t.value
+	case "~":
+		return ok && t.pattern.MatchString(value)
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if !ok || err != nil {
+		return false
+	}
+	switch t.op {
+	case ">":
+		return number > t.number
+	case ">=":
+		return number >= t.number
+	case "<":
+		return number < t.number
+	default:
+		return number <= t.number
+	}
+}
+
+// splitQuoted splits s at spaces
+// keeping double quoted values together
+func splitQuoted(s string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		quoted bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// SetQuery shows only the logs matching the query
+func (pager *Pager) SetQuery(query Query) {
+	pager.query = query
+	pager.Rebuild()
+	pager.render()
+}
+
+// skips reports whether the pager does not show the item
+func (pager *Pager) skips(item ring.Item) bool {
+	return pager.hidden(item.Label) || !pager.query.Match(item.Raw)
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryMatch(t *testing.T) {
+
+	logs := []string{
+		`{"level":"error","msg":"connection timed out","latency":1200,"user":{"id":"42"}}`,
+		`level=info msg="slow request" latency=980`,
+		`{"level":"debug","msg":"cache hit"}`,
+		`plain text line mentioning Timeout`,
+	}
+
+	for _, tc := range []struct {
+		filter string
+		want   []bool
+	}{
+		{filter: "", want: []bool{true, true, true, true}},
+		{filter: "level=error", want: []bool{true, false, false, false}},
+		{filter: "level!=debug", want: []bool{true, true, false, true}},
+		{filter: "latency>=980", want: []bool{true, true, false, false}},
+		{filter: "latency<1000", want: []bool{false, true, false, false}},
+		{filter: `msg="slow request"`, want: []bool{false, true, false, false}},
+		{filter: "msg~timed?.out", want: []bool{true, false, false, false}},
+		{filter: "user.id=42 level=error", want: []bool{true, false, false, false}},
+		{filter: "timeout", want: []bool{false, false, false, true}},
+	} {
+		query, err := ParseQuery(tc.filter)
+		if err != nil {
+			t.Fatalf("[%s] unable to parse query: %v", tc.filter, err)
+		}
+		for i, data := range logs {
+			if got := query.Match(data); got != tc.want[i] {
+				t.Fatalf("[%s] log %d: wanted match %t, got: %t", tc.filter, i, tc.want[i], got)
+			}
+		}
+	}
+
+	for _, filter := range []string{"latency>fast", "msg~(", `msg="open`} {
+		if _, err := ParseQuery(filter); err == nil {
+			t.Fatalf("[%s] wanted an error", filter)
+		}
+	}
+}
+
+func TestPagerShown(t *testing.T) {
+
+	store := New(12)
+	store.SetCollapseMode("", CollapseRepeats)
+	pager := store.NewPager(4, 60, testRefreshRate)
+
+	query, err := ParseQuery("level=error")
+	if err != nil {
+		t.Fatalf("unable to parse query: %v", err)
+	}
+	pager.SetQuery(query)
+	store.Mute("db")
+
+	for _, log := range []struct{ label, data string }{
+		// colored logs match once the colors are removed
+		{"api", "\x1b[31mlevel=error\x1b[0m msg=boom"},
+		{"api", "\x1b[31mlevel=error\x1b[0m msg=boom"},
+		{"api", "level=info msg=ok"},
+		{"db", "level=error msg=timeout"},
+		{"web", "level=error msg=render"},
+	} {
+		store.Insert(log.label, time.Now(), []byte(log.data))
+		pager.MovePosition()
+	}
+
+	// the repeat, the info log and the muted beam are not shown
+	if pager.Shown() != 2 {
+		t.Fatalf("wanted 2 logs shown, got: %d", pager.Shown())
+	}
+}
@@ -0,0 +1,135 @@
+package ring
+
+import "unsafe"
+
+// itemOverhead is the memory an Item occupies
+// without the bytes of its Raw and Styled data
+var itemOverhead = uint64(unsafe.Sizeof(Item{}))
+
+// usage tracks the memory used by the items of a
+// label and the offsets of these items (oldest first)
+type usage struct {
+	bytes   uint64
+	quota   uint64
+	offsets []uint32
+}
+
+// budget bounds the memory used by the items of the
+// buffer. A limit or quota of zero means no bound.
+type budget struct {
+	bytes uint64
+	limit uint64
+	// quota applied to labels without
+	// an individual quota
+	quota  uint64
+	labels map[string]*usage
+	// floor is the offset of the oldest item
+	// not yet evicted by the limit
+	floor uint32
+}
+
+func size(i Item) uint64 {
+	return uint64(len(i.Raw)+len(i.Styled)) + itemOverhead
+}
+
+// Limit sets the maximum number of bytes all items
+// of the buffer may use. Once exceeded the oldest items
+// are evicted.
+func (buf *Buffer) Limit(bytes uint64) {
+	buf.budget.limit = bytes
+}
+
+// Quota sets the maximum number of bytes the items of
+// a label may use. Once exceeded the oldest items of the
+// label are evicted leaving the items of other labels
+// untouched. An empty label sets the quota for all labels
+// without an individual quota.
+func (buf *Buffer) Quota(label string, bytes uint64) {
+	if label == "" {
+		buf.budget.quota = bytes
+		return
+	}
+	buf.usage(label).quota = bytes
+}
+
+// Usage returns the bytes used by all items
+// and the configured limit
+func (buf *Buffer) Usage() (uint64, uint64) {
+	return buf.budget.bytes, buf.budget.limit
+}
+
+func (buf *Buffer) usage(label string) *usage {
+	u, ok := buf.budget.labels[label]
+	if !ok {
+		u = &usage{}
+		buf.budget.labels[label] = u
+	}
+	return u
+}
+
+// account adds the item stored at the offset to the budget
+// and evicts items as long as the limit or the quota of the
+// label is exceeded. The item itself is never evicted.
+func (buf *Buffer) account(offset uint32, i Item) {
+
+	u := buf.usage(i.Label)
+	u.bytes += size(i)
+	u.offsets = append(u.offsets, offset)
+	buf.budget.bytes += size(i)
+
+	quota := u.quota
+	if quota == 0 {
+		quota = buf.budget.quota
+	}
+
+	for quota > 0 && u.bytes > quota && len(u.offsets) > 1 {
+		buf.evict(u.offsets[0])
+	}
+
+	// items below the capacity window are already overwritten
+	if buf.written > buf.capacity && buf.budget.floor < buf.written-buf.capacity {
+		buf.budget.floor = buf.written - buf.capacity
+	}
+
+	for buf.budget.limit > 0 && buf.budget.bytes > buf.budget.limit && buf.budget.floor < offset {
+		buf.evict(buf.budget.floor)
+		buf.budget.floor++
+	}
+}
+
+// release removes an item which is about to be
+// overwritten from the budget
+func (buf *Buffer) release(i Item) {
+	if len(i.Raw) <= 0 {
+		return
+	}
+
+	buf.budget.bytes -= size(i)
+
+	u := buf.usage(i.Label)
+	u.bytes -= size(i)
+	// items are overwritten in the order they have been
+	// inserted so the item must be the oldest of its label
+	if len(u.offsets) > 0 && u.offsets[0] == i.index-1 {
+		u.offsets = u.offsets[1:]
+	}
+}
+
+// evict removes the data of the item at the offset while
+// keeping its index and label. Evicted items have no Raw
+// data and are flagged as Evicted.
+func (buf *Buffer) evict(offset uint32) {
+	slot := buf.marshalIndex(offset)
+	item := buf.data[slot]
+
+	// slot has already been overwritten or evicted
+	if item.index != offset+1 || len(item.Raw) <= 0 {
+		return
+	}
+
+	buf.release(item)
+
+	item.Raw, item.Styled = "", ""
+	item.Evicted = true
+	buf.data[slot] = item
+}
@@ -0,0 +1,77 @@
+package ring
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimitEvictsOldest(t *testing.T) {
+
+	buffer := New(16)
+	// room for 3 items
+	buffer.Limit(3 * (itemOverhead + 10))
+
+	for i := 0; i < 5; i++ {
+		buffer.Insert(Item{Label: "svc", Raw: strings.Repeat("x", 10)})
+	}
+
+	used, _ := buffer.Usage()
+	if used != 3*(itemOverhead+10) {
+		t.Fatalf("wanted usage: %d - got: %d", 3*(itemOverhead+10), used)
+	}
+
+	oldest, latest, _ := buffer.Window()
+	if oldest != 2 || latest != 4 {
+		t.Fatalf("wanted window: 2-4 - got: %d-%d", oldest, latest)
+	}
+
+	for offset := uint32(0); offset < 2; offset++ {
+		item := buffer.At(offset)
+		if !item.Evicted || item.Raw != "" || item.Label != "svc" {
+			t.Fatalf("[%d] wanted evicted item with label - got: %+v", offset, item)
+		}
+	}
+}
+
+func TestQuotaKeepsOtherBeams(t *testing.T) {
+
+	buffer := New(16)
+	buffer.Quota("", 2*(itemOverhead+10))
+
+	buffer.Insert(Item{Label: "quiet", Raw: strings.Repeat("q", 10)})
+	for i := 0; i < 6; i++ {
+		buffer.Insert(Item{Label: "chatty", Raw: strings.Repeat("c", 10)})
+	}
+
+	if item := buffer.At(0); item.Evicted {
+		t.Fatalf("wanted item of quiet beam to be kept - got: %+v", item)
+	}
+
+	var kept int
+	for offset := uint32(1); offset < 7; offset++ {
+		if !buffer.At(offset).Evicted {
+			kept++
+		}
+	}
+	if kept != 2 {
+		t.Fatalf("wanted 2 items of chatty beam - got: %d", kept)
+	}
+}
+
+func TestOverwriteReleasesBytes(t *testing.T) {
+
+	buffer := New(2)
+	buffer.Limit(1 << 20)
+
+	for i := 0; i < 10; i++ {
+		buffer.Insert(Item{Label: "svc", Raw: strings.Repeat("x", 10)})
+	}
+
+	used, _ := buffer.Usage()
+	if used != 2*(itemOverhead+10) {
+		t.Fatalf("wanted usage of 2 items: %d - got: %d", 2*(itemOverhead+10), used)
+	}
+	if n := len(buffer.budget.labels["svc"].offsets); n != 2 {
+		t.Fatalf("wanted 2 tracked offsets - got: %d", n)
+	}
+}
@@ -1,7 +1,13 @@
 package ring
 
+import "time"
+
 type Reader interface {
 	At(i uint32) Item
+	// Window returns the offsets of the oldest and
+	// the latest item still present in the buffer.
+	// ok is false as long as the buffer is empty
+	Window() (oldest uint32, latest uint32, ok bool)
 	// Range(start int, size int) Slice
 	OffsetRead(offset int, buf []Item)
 	// HasData does an assuption whether
@@ -14,41 +20,69 @@ type Reader interface {
 type Slice []Item
 
 // Item represents one element in the Buffer.
-// While fields such as the index or the label
-// seem clear the field Raw needs some explaining.
-// The fiel Raw includes the entire row/line finished
-// formatted/colored/build stored at a given index.
-// In order to retrieve only the application log
-// Item has a DataPointer which must be provided and
-// indicated at which index of Raw the application log
-// start. To get only the application log one can do
-// the following:
-//
-//	item = Item {
-//			index: 0,
-//			Label: "test"
-//			Raw: "test | level=debug, msg=I am the application log",
-//			DataPointer: 7, // -> len("test | ") where "test | " is the formatted/build line prefix
-//	}
-//
-// log := item.Raw[item.DataPointer:] // == log := "level=debug, msg=I am the application log"
+// Raw holds the application log as it has been
+// received without ANSI escape sequences; Styled holds
+// the log as shown if it differs (such as with the colors
+// of the application). Anything displayed around it
+// (such as the colored label) is rendered at view
+// time and not part of the Item.
+// Received is the time scotty received the log while
+// Logged is the time found in the log itself (zero if
+// not available or not requested).
+// Repeats counts how often the log was received again
+// right after itself (see Buffer.Repeat) and LastSeen
+// is the time it was last received.
 type Item struct {
-	index       uint32
-	Label       string
-	Raw         string
-	DataPointer int
-	Revision    uint8
+	index    uint32
+	Label    string
+	Received time.Time
+	Logged   time.Time
+	Raw      string
+	Styled   string
+	Revision uint8
+	Repeats  uint32
+	LastSeen time.Time
+	// Evicted is true if the item was removed to stay
+	// within the memory limits of the buffer. Evicted
+	// items have no Raw data.
+	Evicted bool
 }
 
 func (i Item) Index() uint32 {
 	return i.index
 }
 
+// Display returns the log as shown: Styled if
+// set or else Raw
+func (i Item) Display() string {
+	if i.Styled != "" {
+		return i.Styled
+	}
+	return i.Raw
+}
+
+// Time returns the time the log was written if known
+// or else the time the log was received.
+func (i Item) Time() time.Time {
+	if !i.Logged.IsZero() {
+		return i.Logged
+	}
+	return i.Received
+}
+
+// minSlots is the number of slots
+// allocated by the first insert
+const minSlots = 1024
+
 type Buffer struct {
 	capacity uint32
 	head     uint32
 	written  uint32
-	data     []Item
+	// data grows up to the capacity as items are
+	// inserted such that a buffer bounded by its
+	// budget only takes the memory of its items
+	data   []Item
+	budget budget
 }
 
 func New(size uint32) *Buffer {
@@ -56,23 +90,80 @@ func New(size uint32) *Buffer {
 		capacity: size,
 		head:     0,
 		written:  0,
-		data:     make([]Item, size, size),
+		data:     nil,
+		budget: budget{
+			labels: make(map[string]*usage),
+		},
 	}
 }
 
 // Insert sets the given item at the next writing position
 // of the buffer.
 func (buf *Buffer) Insert(i Item) {
+	buf.grow()
+	buf.release(buf.data[buf.head])
+
 	buf.written += 1
 	i.index = buf.written
 
 	buf.data[buf.head] = i
 	buf.head = (buf.head + 1) % buf.capacity
+
+	buf.account(i.index-1, i)
+}
+
+// Repeat counts the item at the offset as received once more
+// at the given time instead of inserting it again. It reports
+// false if the item is no longer in the buffer.
+func (buf *Buffer) Repeat(offset uint32, received time.Time) bool {
+	slot := buf.marshalIndex(offset)
+	if int(slot) >= len(buf.data) {
+		return false
+	}
+	item := &buf.data[slot]
+	if item.index != offset+1 || item.Evicted {
+		return false
+	}
+
+	item.Repeats += 1
+	item.LastSeen = received
+	item.Revision += 1
+	return true
 }
 
 // At returns an item at a given index of the buffer
 func (buf *Buffer) At(i uint32) Item {
-	return buf.data[buf.marshalIndex(i)]
+	return buf.slot(buf.marshalIndex(i))
+}
+
+// slot returns the item of the slot. Slots not
+// yet allocated hold an empty item.
+func (buf *Buffer) slot(i uint32) Item {
+	if int(i) >= len(buf.data) {
+		return Item{}
+	}
+	return buf.data[i]
+}
+
+// grow allocates more slots if the next item is to
+// be written beyond the allocated slots. The slots are
+// doubled but never exceed the capacity.
+func (buf *Buffer) grow() {
+	if int(buf.head) < len(buf.data) {
+		return
+	}
+
+	size := 2 * len(buf.data)
+	if size < minSlots {
+		size = minSlots
+	}
+	if size > int(buf.capacity) {
+		size = int(buf.capacity)
+	}
+
+	data := make([]Item, size)
+	copy(data, buf.data)
+	buf.data = data
 }
 
 // Head returns the latest index written to
@@ -98,7 +189,7 @@ func (buf *Buffer) Range(start int, size int) Slice {
 
 	for i := start; i < start+size; i++ {
 		index = buf.marshalIndex(uint32(i))
-		out = append(out, buf.data[index])
+		out = append(out, buf.slot(index))
 	}
 
 	return out
@@ -116,8 +207,24 @@ func (buf *Buffer) OffsetRead(offset int, b []Item) {
 
 	for i, j := offset, 0; i < offset+len(b); i, j = i+1, j+1 {
 		index = buf.marshalIndex(uint32(i))
-		b[j] = buf.data[index]
+		b[j] = buf.slot(index)
+	}
+}
+
+func (buf *Buffer) Window() (uint32, uint32, bool) {
+	if buf.written == 0 {
+		return 0, 0, false
 	}
+
+	var oldest uint32
+	if buf.written > buf.capacity {
+		oldest = buf.written - buf.capacity
+	}
+	if buf.budget.floor > oldest {
+		oldest = buf.budget.floor
+	}
+
+	return oldest, buf.written - 1, true
 }
 
 func (buf *Buffer) HasData(index uint32) bool {
@@ -125,7 +232,7 @@ func (buf *Buffer) HasData(index uint32) bool {
 		return false
 	}
 
-	return len(buf.data[buf.marshalIndex(index)].Raw) > 0
+	return len(buf.slot(buf.marshalIndex(index)).Raw) > 0
 }
 
 func (buf *Buffer) marshalIndex(absolute uint32) uint32 {
@@ -3,6 +3,7 @@ package ring
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestRangeNoOverflow(t *testing.T) {
@@ -315,6 +316,59 @@ func TestOffsetRead(t *testing.T) {
 	}
 }
 
+func TestRepeat(t *testing.T) {
+
+	buffer := New(2)
+	buffer.Insert(Item{Raw: "GET /health 200"})
+
+	seen := time.Now()
+	if !buffer.Repeat(0, seen) || !buffer.Repeat(0, seen) {
+		t.Fatalf("wanted the buffered item to be repeated")
+	}
+	if item := buffer.At(0); item.Repeats != 2 || !item.LastSeen.Equal(seen) {
+		t.Fatalf("wanted 2 repeats last seen at %v, got: %d at %v", seen, item.Repeats, item.LastSeen)
+	}
+	if _, latest, _ := buffer.Window(); latest != 0 {
+		t.Fatalf("wanted repeats not to take a slot, got latest offset: %d", latest)
+	}
+
+	buffer.Insert(Item{Raw: "GET /users 200"})
+	buffer.Insert(Item{Raw: "GET /health 200"})
+	if buffer.Repeat(0, seen) {
+		t.Fatalf("wanted an overwritten item not to be repeated")
+	}
+	if item := buffer.At(2); item.Repeats != 0 {
+		t.Fatalf("wanted the item overwriting the slot without repeats, got: %d", item.Repeats)
+	}
+}
+
+func TestGrow(t *testing.T) {
+
+	buffer := New(1500)
+	if len(buffer.data) != 0 {
+		t.Fatalf("wanted no slots before the first insert, got: %d", len(buffer.data))
+	}
+
+	buffer.Insert(Item{Raw: "Line-0"})
+	if len(buffer.data) != minSlots {
+		t.Fatalf("wanted %d slots after the first insert, got: %d", minSlots, len(buffer.data))
+	}
+
+	for i := 1; i < 3000; i++ {
+		buffer.Insert(Item{Raw: fmt.Sprintf("Line-%d", i)})
+	}
+	if len(buffer.data) != 1500 {
+		t.Fatalf("wanted the slots to stop growing at the capacity, got: %d", len(buffer.data))
+	}
+
+	oldest, latest, _ := buffer.Window()
+	for _, offset := range []uint32{oldest, 2000, latest} {
+		if item := buffer.At(offset); item.Raw != fmt.Sprintf("Line-%d", offset) {
+			t.Fatalf("wanted Line-%d at offset %d, got: %q", offset, offset, item.Raw)
+		}
+	}
+}
+
 /*
 Current benchmark results
 
@@ -330,9 +384,8 @@ func BenchmarkOffsetRead(b *testing.B) {
 
 	for i := 0; i < 2048; i++ {
 		buf.Insert(Item{
-			Label:       "hello-world",
-			Raw:         `hello-world | {"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`,
-			DataPointer: 14,
+			Label: "hello-world",
+			Raw:   `{"level":"warn","ts":1680212791.946584,"caller":"application/structred.go:39","msg":"caution this indicates X","index":998,"ts":1680212791.946579}`,
 		})
 	}
 
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// scrollStep is the number of columns
+	// scrolled horizontally at once
+	scrollStep = 8
+	// resetSequence resets all styles
+	resetSequence = "\x1b[0m"
+)
+
+// clip returns the columns from to from+width of the line.
+// Escape sequences are kept even if left of the columns
+// such that the clipped part keeps its colors. Wide grapheme
+// clusters cut by the left edge are replaced by spaces and
+// newlines by a space.
+func clip(line string, from int, width int) string {
+	if width < 1 {
+		return ""
+	}
+
+	var builder strings.Builder
+	var col int
+	var escaped bool
+	segments(line, func(seg segment) bool {
+		if seg.escape {
+			escaped = true
+			builder.WriteString(seg.text)
+			return true
+		}
+		if col >= from+width {
+			// escape sequences after the columns
+			// are of no use
+			return false
+		}
+		if seg.newline {
+			seg = segment{text: " ", width: 1}
+		}
+
+		switch {
+		case col >= from && col+seg.width <= from+width:
+			builder.WriteString(seg.text)
+		case col < from && col+seg.width > from:
+			builder.WriteString(strings.Repeat(" ", col+seg.width-from))
+		}
+		col += seg.width
+		return true
+	})
+
+	if escaped {
+		builder.WriteString(resetSequence)
+	}
+	return builder.String()
+}
+
+// clipLine returns the prefix followed by the columns of the
+// data starting at the column which fit the width. The prefix
+// stays in place while the data scrolls.
+func clipLine(prefix string, data string, column int, width int) string {
+	return prefix + clip(data, column, width-displayWidth(prefix))
+}
+
+// scrollColumn returns the column after scrolling
+// delta steps (left if negative)
+func scrollColumn(column int, delta int) int {
+	return clamp(column + delta*scrollStep)
+}
+
+// ToggleWrap turns wrapping of lines wider than the page on or
+// off. Without wrapping each log takes a single line which is
+// cut at the width of the page and can be scrolled horizontally.
+func (pager *Pager) ToggleWrap() {
+	pager.nowrap = !pager.nowrap
+	pager.column = 0
+	pager.redraw()
+}
+
+// Wrapping reports whether lines wider than the page are wrapped
+func (pager *Pager) Wrapping() bool {
+	return !pager.nowrap
+}
+
+// ScrollHorizontal scrolls the data of the logs delta steps to the
+// right (left if negative) keeping the line prefix in place.
+// Wrapping is turned off to scroll.
+func (pager *Pager) ScrollHorizontal(delta int) error {
+	if pager.table != nil {
+		return fmt.Errorf("the table view cannot be scrolled horizontally")
+	}
+	pager.nowrap = true
+	pager.column = scrollColumn(pager.column, delta)
+	pager.redraw()
+	return nil
+}
+
+// Column returns the first column of the data shown
+func (pager *Pager) Column() int {
+	return pager.column
+}
+
+// redraw rebuilds the page in place: a paused page keeps showing
+// the same logs, a tailing page shows the latest logs
+func (pager *Pager) redraw() {
+	pager.Rebuild()
+	switch {
+	case !pager.paused:
+		pager.Refresh()
+	case pager.sorted != nil:
+		pager.renderSorted()
+	default:
+		pager.Jump(TargetIndex(pager.top))
+	}
+}
+
+// ToggleWrap turns wrapping of the pretty printed log in the
+// modal on or off. Without wrapping the modal and the logs
+// behind it can be scrolled horizontally.
+func (formatter *Formatter) ToggleWrap() {
+	formatter.nowrap = !formatter.nowrap
+	formatter.column = 0
+	formatter.buildView()
+}
+
+// Wrapping reports whether the log in the modal is wrapped
+func (formatter *Formatter) Wrapping() bool {
+	return !formatter.nowrap
+}
+
+// ScrollHorizontal scrolls the data of the logs delta steps to
+// the right (left if negative) keeping the line prefix in place.
+// Wrapping is turned off to scroll.
+func (formatter *Formatter) ScrollHorizontal(delta int) {
+	formatter.nowrap = true
+	formatter.column = scrollColumn(formatter.column, delta)
+	formatter.buildView()
+}
+
+// clipLines clips each line of s to the columns
+// starting at the column which fit the width
+func clipLines(s string, column int, width int) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = clip(line, column, width)
+	}
+	return strings.Join(lines, "\n")
+}
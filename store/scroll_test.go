@@ -0,0 +1,90 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClip(t *testing.T) {
+
+	tt := []struct {
+		name        string
+		line        string
+		from, width int
+		want        string
+	}{
+		{
+			name: "fits",
+			line: "hello",
+			from: 0, width: 10,
+			want: "hello",
+		},
+		{
+			name: "scrolled",
+			line: "hello world",
+			from: 6, width: 3,
+			want: "wor",
+		},
+		{
+			name: "past the end",
+			line: "hello",
+			from: 8, width: 3,
+			want: "",
+		},
+		{
+			name: "colors left of the columns are kept",
+			line: "\x1b[31mred\x1b[0m plain",
+			from: 1, width: 4,
+			want: "\x1b[31med\x1b[0m p" + resetSequence,
+		},
+		{
+			name: "wide runes",
+			line: "ab日本語",
+			from: 3, width: 5,
+			want: " 本語",
+		},
+		{
+			name: "wide rune at the right edge",
+			line: "日本語",
+			from: 0, width: 3,
+			want: "日",
+		},
+	}
+
+	for _, tc := range tt {
+		if got := clip(tc.line, tc.from, tc.width); got != tc.want {
+			t.Fatalf("[%s] wanted: %q - got: %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPagerNoWrap(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(2, 20, testRefreshRate)
+
+	store.Insert("api", time.Now(), []byte("0123456789abcdefghij"))
+	pager.MovePosition()
+	pager.Refresh()
+	if lines := strings.Split(pager.String(), "\n"); lines[0] != "api | 0123456789abcd" || lines[1] != "    | efghij" {
+		t.Fatalf("wanted the log wrapped into two lines, got: %q", lines)
+	}
+
+	pager.ToggleWrap()
+	if lines := strings.Split(pager.String(), "\n"); lines[0] != "api | 0123456789abcd" {
+		t.Fatalf("wanted the log cut at the width, got: %q", lines)
+	}
+
+	if err := pager.ScrollHorizontal(1); err != nil {
+		t.Fatalf("unable to scroll: %v", err)
+	}
+	if lines := strings.Split(pager.String(), "\n"); lines[0] != "api | 89abcdefghij" {
+		t.Fatalf("wanted the label pinned while scrolling, got: %q", lines)
+	}
+
+	pager.ScrollHorizontal(-2)
+	if pager.Column() != 0 {
+		t.Fatalf("wanted scrolling to stop at the first column, got: %d", pager.Column())
+	}
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/store/ring"
+)
+
+// Target describes a position in the buffer a view
+// can jump to. A Target is either an index or a point
+// in time.
+type Target struct {
+	index  uint32
+	at     time.Time
+	byTime bool
+}
+
+// TargetIndex creates a Target pointing to the index
+func TargetIndex(index uint32) Target {
+	return Target{index: index}
+}
+
+var clockLayouts = []string{
+	"15:04:05.000",
+	"15:04:05",
+	"15:04",
+}
+
+// ParseTarget parses the input of a jump prompt. Valid inputs are
+// an index (42), a time of today (14:32:05, 14:32 or 14:32:05.120)
+// or a duration relative to now (30s, 5m, 1h30m) which refers to
+// the first log received within the last N.
+func ParseTarget(input string, now time.Time) (Target, error) {
+
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Target{}, fmt.Errorf("nothing to jump to")
+	}
+
+	if index, err := strconv.ParseUint(input, 10, 32); err == nil {
+		return Target{index: uint32(index)}, nil
+	}
+
+	if strings.Contains(input, ":") {
+		for _, layout := range clockLayouts {
+			clock, err := time.ParseInLocation(layout, input, now.Location())
+			if err != nil {
+				continue
+			}
+			at := time.Date(now.Year(), now.Month(), now.Day(),
+				clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), now.Location())
+			return Target{at: at, byTime: true}, nil
+		}
+		return Target{}, fmt.Errorf("%q is not a valid time (use hh:mm:ss)", input)
+	}
+
+	d, err := time.ParseDuration(strings.TrimPrefix(input, "-"))
+	if err != nil {
+		return Target{}, fmt.Errorf("%q is neither an index, a time (hh:mm:ss) nor a duration (30s)", input)
+	}
+
+	return Target{at: now.Add(-d), byTime: true}, nil
+}
+
+// ValidTargetInput reports whether s could become a valid target
+// while the user is still typing.
+func ValidTargetInput(s string) error {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789:.-hmsuµn", r) {
+			return fmt.Errorf("invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// resolve returns the offset in the buffer the target points to.
+// For time based targets the first item with a time at or after
+// the target time is returned.
+func (target Target) resolve(reader ring.Reader) (uint32, error) {
+
+	oldest, latest, ok := reader.Window()
+	if !ok {
+		return 0, fmt.Errorf("no logs received yet")
+	}
+
+	if !target.byTime {
+		if target.index < oldest || target.index > latest {
+			return 0, fmt.Errorf("index %d is not in the buffer (available: %d-%d)", target.index, oldest, latest)
+		}
+		return target.index, nil
+	}
+
+	// items from different beams are not guaranteed to be
+	// ordered by time (if the log's own time is used) hence
+	// no binary search
+	for offset := oldest; offset <= latest; offset++ {
+		if !reader.At(offset).Time().Before(target.at) {
+			return offset, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no logs at or after %s", target.at.Format("15:04:05"))
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseTarget(t *testing.T) {
+
+	now := time.Date(2023, 8, 16, 14, 40, 0, 0, time.Local)
+
+	tt := []struct {
+		input   string
+		want    Target
+		wantErr bool
+	}{
+		{input: "42", want: Target{index: 42}},
+		{input: "14:32:05", want: Target{at: time.Date(2023, 8, 16, 14, 32, 5, 0, time.Local), byTime: true}},
+		{input: "14:32", want: Target{at: time.Date(2023, 8, 16, 14, 32, 0, 0, time.Local), byTime: true}},
+		{input: "30s", want: Target{at: now.Add(-time.Second * 30), byTime: true}},
+		{input: "-5m", want: Target{at: now.Add(-time.Minute * 5), byTime: true}},
+		{input: "14:", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseTarget(tc.input, now)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("[%q] wanted error - got target: %+v", tc.input, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[%q] unexpected error: %v", tc.input, err)
+		}
+		if got.index != tc.want.index || got.byTime != tc.want.byTime || !got.at.Equal(tc.want.at) {
+			t.Fatalf("[%q] wanted target: %+v - got: %+v", tc.input, tc.want, got)
+		}
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+
+	store := New(8)
+	start := time.Date(2023, 8, 16, 14, 0, 0, 0, time.Local)
+
+	// 12 items for a buffer of 8 - the first 4 are overwritten
+	for i := 0; i < 12; i++ {
+		store.Insert("test", start.Add(time.Second*time.Duration(i)), []byte(fmt.Sprintf("Line-%d", i)))
+	}
+
+	tt := []struct {
+		name    string
+		target  Target
+		want    uint32
+		wantErr bool
+	}{
+		{name: "index in buffer", target: TargetIndex(6), want: 6},
+		{name: "overwritten index", target: TargetIndex(2), wantErr: true},
+		{name: "future index", target: TargetIndex(12), wantErr: true},
+		{name: "exact time", target: Target{at: start.Add(time.Second * 9), byTime: true}, want: 9},
+		{name: "time between logs", target: Target{at: start.Add(time.Millisecond * 5500), byTime: true}, want: 6},
+		{name: "time before buffer", target: Target{at: start, byTime: true}, want: 4},
+		{name: "time after buffer", target: Target{at: start.Add(time.Minute), byTime: true}, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		got, err := tc.target.resolve(store.buffer)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("[%s] wanted error - got offset: %d", tc.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("[%s] unexpected error: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("[%s] wanted offset: %d - got: %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestLogTime(t *testing.T) {
+
+	want := time.Date(2023, 8, 16, 17, 6, 36, 0, time.UTC)
+
+	tt := []struct {
+		name   string
+		data   string
+		fields []string
+	}{
+		{name: "zap unix seconds", data: fmt.Sprintf(`{"level":"info","ts":%d.0,"msg":"hello"}`, want.Unix()), fields: []string{"ts"}},
+		{name: "unix millis", data: fmt.Sprintf(`{"time":%d}`, want.UnixMilli()), fields: []string{"ts", "time"}},
+		{name: "json rfc3339", data: `{"time":"2023-08-16T19:06:36+02:00"}`, fields: []string{"time"}},
+		{name: "logrus text", data: `time="2023-08-16T19:06:36+02:00" level=error msg="unable to do X"`, fields: []string{"time"}},
+		{name: "logfmt unquoted", data: `ts=2023-08-16T17:06:36Z level=info`, fields: []string{"ts"}},
+	}
+
+	for _, tc := range tt {
+		if got := logTime(tc.data, tc.fields); !got.Equal(want) {
+			t.Fatalf("[%s] wanted time: %s - got: %s", tc.name, want, got)
+		}
+	}
+
+	if got := logTime(`{"msg":"no time"}`, []string{"ts"}); !got.IsZero() {
+		t.Fatalf("wanted zero time for log without time field - got: %s", got)
+	}
+}
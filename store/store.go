@@ -1,58 +1,230 @@
 package store
 
 import (
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/KonstantinGasser/scotty/store/ring"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type Store struct {
 	buffer *ring.Buffer
+	// prefix is shared with all pagers and formatters
+	// created by the store
+	prefix *prefixer
+	// filter is shared with all pagers created
+	// by the store
+	filter *filter
+	// timeFields are the fields looked up in a log
+	// to find the time the log was written
+	timeFields []string
+	// payloads maps beams to the format their logs are
+	// pretty printed in. Shared with all formatters.
+	payloads map[string]string
+	// bookmarks are shared with the prefixer
+	// and all formatters
+	bookmarks bookmarks
+	// restored are the bookmarks of
+	// previous sessions
+	restored []Bookmark
+	// nowrap is the initial wrapping of
+	// new pagers and formatters
+	nowrap bool
+	// ansi decides what happens to the escape
+	// sequences of the logs of each beam
+	ansi ansiPolicies
+	// collapse counts logs repeating the previous
+	// log of their beam instead of storing them
+	collapse collapser
 }
 
 func New(size uint32) *Store {
+	buffer := ring.New(size)
+	marks := bookmarks{}
+
+	prefix := newPrefixer(buffer)
+	prefix.bookmarks = marks
 	return &Store{
-		buffer: ring.New(size),
+		buffer:    buffer,
+		prefix:    prefix,
+		filter:    newFilter(),
+		payloads:  map[string]string{},
+		bookmarks: marks,
+		ansi:      ansiPolicies{labels: map[string]ANSIPolicy{}},
+		collapse: collapser{
+			labels: map[string]CollapseMode{},
+			latest: map[string]collapsed{},
+		},
 	}
 }
 
-func (store *Store) Insert(label string, offset int, data []byte) {
+// Insert adds the log of the beam to the buffer. Escape
+// sequences are removed from the data queried and searched
+// and kept for display as the beam's ANSIPolicy says. Logs
+// only made of escape sequences are dropped. Logs repeating
+// the previous log of their beam are counted as its repeats
+// as the beam's CollapseMode says.
+func (store *Store) Insert(label string, received time.Time, data []byte) {
+	raw, styled := store.ansi.split(label, string(data))
+	if raw == "" {
+		return
+	}
+
+	key, collapse := store.collapse.key(label, raw)
+	if collapse {
+		latest, ok := store.collapse.latest[label]
+		if ok && latest.key == key && store.buffer.Repeat(latest.offset, received) {
+			return
+		}
+	}
+
+	store.prefix.register(label)
 	store.buffer.Insert(ring.Item{
-		Label:       label,
-		Raw:         string(data),
-		DataPointer: offset,
+		Label:    label,
+		Received: received,
+		Logged:   logTime(raw, store.timeFields),
+		Raw:      raw,
+		Styled:   styled,
+	})
+
+	if collapse {
+		_, offset, _ := store.buffer.Window()
+		store.collapse.latest[label] = collapsed{offset: offset, key: key}
+	}
+}
+
+// SetANSIPolicy sets what happens to the escape sequences of the
+// logs of the beam. An empty label sets the policy of all beams
+// without their own.
+func (store *Store) SetANSIPolicy(label string, policy ANSIPolicy) {
+	if label == "" {
+		store.ansi.fallback = policy
+		return
+	}
+	store.ansi.labels[label] = policy
+}
+
+// SetCollapseMode sets whether logs of the beam repeating the
+// previous log of the beam are counted as its repeats. An empty
+// label sets the mode of all beams without their own.
+func (store *Store) SetCollapseMode(label string, mode CollapseMode) {
+	if label == "" {
+		store.collapse.fallback = mode
+		return
+	}
+	store.collapse.labels[label] = mode
+}
+
+// PreferLogTime makes the store look for the time a log
+// was written in the given fields. If found the time is used
+// instead of the time scotty received the log.
+func (store *Store) PreferLogTime(fields ...string) {
+	store.timeFields = fields
+}
+
+// SetTimeMode sets how the time of a log is
+// shown in the line prefix
+func (store *Store) SetTimeMode(mode TimeMode) {
+	store.prefix.timeMode = mode
+}
+
+// ShowIndex shows the index of each log in
+// front of the line prefix if true
+func (store *Store) ShowIndex(show bool) {
+	store.prefix.index = show
+}
+
+// WrapLines sets whether pagers and formatters created
+// afterwards wrap lines wider than the page or cut them
+func (store *Store) WrapLines(wrap bool) {
+	store.nowrap = !wrap
+}
+
+// SetColor sets the color the label is rendered
+// with in the line prefix
+func (store *Store) SetColor(label string, color lipgloss.Color) {
+	store.prefix.setColor(label, color)
+}
+
+// Highlight colors the divider of the line prefix for
+// any log matching the pattern. Patterns are checked in
+// the order they have been added.
+func (store *Store) Highlight(pattern *regexp.Regexp, color lipgloss.Color) {
+	store.prefix.highlights = append(store.prefix.highlights, highlight{
+		pattern: pattern,
+		divider: lipgloss.NewStyle().Bold(true).Foreground(color).Render(divider),
 	})
 }
 
 func (store Store) NewPager(size uint8, width int, refresh time.Duration) Pager {
 	buf := make([]string, size)
+	offsets := make([]int, size)
 	for i := range buf {
 		buf[i] = "\000"
+		offsets[i] = noItem
 	}
 
 	var ticker *time.Ticker
 	if refresh > 0 {
 		ticker = time.NewTicker(refresh)
 	}
+
+	// pagers created while logs are buffered
+	// continue after the latest log
+	var position uint32
+	if _, latest, ok := store.buffer.Window(); ok {
+		position = latest + 1
+	}
 	return Pager{
 		size:       size,
 		ttyWidth:   width,
 		reader:     store.buffer,
-		position:   0,
+		prefix:     store.prefix,
+		filter:     store.filter,
+		bookmarks:  store.bookmarks,
+		position:   position,
 		buffer:     buf,
+		offsets:    offsets,
 		written:    0,
 		bufferView: strings.Join(buf, "\n"),
 		ticker:     ticker,
+		nowrap:     store.nowrap,
 	}
 }
 
 func (store Store) NewFormatter(size uint8, width int) Formatter {
 	return Formatter{
-		size:     size,
-		ttyWidth: width,
-		reader:   store.buffer,
-		absolute: 0,
-		relative: 0,
+		size:      size,
+		ttyWidth:  width,
+		reader:    store.buffer,
+		prefix:    store.prefix,
+		payloads:  store.payloads,
+		bookmarks: store.bookmarks,
+		pinned:    map[string]bool{},
+		nowrap:    store.nowrap,
+		absolute:  0,
+		relative:  0,
 	}
 }
+
+// LimitBytes bounds the memory used by the buffered logs.
+// Once exceeded the oldest logs are evicted. Zero disables
+// the limit.
+func (store *Store) LimitBytes(bytes uint64) {
+	store.buffer.Limit(bytes)
+}
+
+// Quota bounds the memory used by the logs of a single beam
+// such that a chatty beam only evicts its own history. An
+// empty label sets the quota of all beams without their own.
+func (store *Store) Quota(label string, bytes uint64) {
+	store.buffer.Quota(label, bytes)
+}
+
+// Usage returns the bytes currently used by the
+// buffered logs and the configured limit
+func (store *Store) Usage() (uint64, uint64) {
+	return store.buffer.Usage()
+}
@@ -0,0 +1,291 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KonstantinGasser/scotty/store/ring"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/ansi"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// maxColumnWidth limits the width of all
+	// but the last column which takes the rest
+	maxColumnWidth = 32
+	columnGap      = "  "
+	ellipsis       = "…"
+)
+
+var (
+	headerStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+)
+
+// table renders items as rows of the values of the
+// columns. Each item takes exactly one line.
+type table struct {
+	columns []string
+	// sortBy is the column the rows of a
+	// sorted page are ordered by
+	sortBy     string
+	descending bool
+}
+
+// fieldValue returns the value of the field in the data which
+// is either a JSON object or a logfmt line
+func fieldValue(data string, field string) (string, bool) {
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		value, err := lookupField(data, field)
+		return value, err == nil
+	}
+
+	match := logfmtValue(field).FindStringSubmatch(data)
+	if match == nil {
+		return "", false
+	}
+	if match[1] != "" {
+		return match[1], true
+	}
+	return match[2], true
+}
+
+// render returns the header and one row per item. Columns are
+// as wide as their widest value (limited to maxColumnWidth)
+// and rows are truncated to the width.
+func (t *table) render(prefix *prefixer, items []ring.Item, width int) []string {
+
+	cells := make([][]string, len(items))
+	widths := make([]int, len(t.columns))
+	for i, column := range t.columns {
+		widths[i] = len(column)
+	}
+
+	for row, item := range items {
+		cells[row] = make([]string, len(t.columns))
+		for i, column := range t.columns {
+			value, _ := fieldValue(item.Raw, column)
+			// values must stay on their row
+			value = strings.Join(strings.Fields(value), " ")
+			cells[row][i] = value
+			if w := ansi.PrintableRuneWidth(value); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i := range widths[:clamp(len(widths)-1)] {
+		if widths[i] > maxColumnWidth {
+			widths[i] = maxColumnWidth
+		}
+	}
+
+	// the header is aligned with the
+	// values following the line prefix
+	var prefixWidth int
+	for _, item := range items {
+		if w := ansi.PrintableRuneWidth(prefix.render(item)); w > prefixWidth {
+			prefixWidth = w
+		}
+	}
+
+	header := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		switch {
+		case column == t.sortBy && t.descending:
+			column += " ▼"
+		case column == t.sortBy:
+			column += " ▲"
+		}
+		header[i] = headerStyle.Render(column)
+	}
+
+	lines := make([]string, 0, len(items)+1)
+	lines = append(lines, fit(strings.Repeat(" ", prefixWidth)+t.row(header, widths), width))
+	for row, item := range items {
+		lines = append(lines, fit(prefix.render(item)+t.row(cells[row], widths), width))
+	}
+	return lines
+}
+
+// row pads the cells to the widths of their column
+// truncating cells which are too wide. Missing values
+// are blank.
+func (t *table) row(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i > 0 {
+			b.WriteString(columnGap)
+		}
+		if i == len(cells)-1 {
+			b.WriteString(cell)
+			break
+		}
+		cell = fit(cell, widths[i])
+		b.WriteString(cell)
+		b.WriteString(strings.Repeat(" ", clamp(widths[i]-ansi.PrintableRuneWidth(cell))))
+	}
+	return b.String()
+}
+
+// fit truncates s to the width ending with an
+// ellipsis if s is wider than the width
+func fit(s string, width int) string {
+	if ansi.PrintableRuneWidth(s) <= width {
+		return s
+	}
+	return truncate.StringWithTail(s, uint(width), ellipsis)
+}
+
+// sort orders the offsets by the value of the sortBy column.
+// Numbers are compared by their value; items without the
+// field are placed last.
+func (t *table) sort(reader ring.Reader, offsets []uint32) {
+
+	type key struct {
+		value   string
+		number  float64
+		numeric bool
+		missing bool
+	}
+
+	keys := make(map[uint32]key, len(offsets))
+	for _, offset := range offsets {
+		value, ok := fieldValue(reader.At(offset).Raw, t.sortBy)
+		number, err := strconv.ParseFloat(value, 64)
+		keys[offset] = key{value: value, number: number, numeric: err == nil, missing: !ok}
+	}
+
+	sort.SliceStable(offsets, func(i, j int) bool {
+		a, b := keys[offsets[i]], keys[offsets[j]]
+		if a.missing || b.missing {
+			return !a.missing && b.missing
+		}
+		if t.descending {
+			a, b = b, a
+		}
+		if a.numeric && b.numeric {
+			return a.number < b.number
+		}
+		return a.value < b.value
+	})
+}
+
+// lines returns the lines the item takes on the page.
+// In table mode the row is rendered with the page.
+func (pager *Pager) lines(item ring.Item) []string {
+	if pager.table != nil {
+		return []string{""}
+	}
+	if pager.nowrap {
+		return []string{clipLine(pager.prefix.render(item), item.Display(), pager.column, pager.ttyWidth)}
+	}
+	return lineWrap(pager.prefix.render(item), item.Display(), pager.ttyWidth)
+}
+
+// SetColumns renders the page as table of the columns
+// or as lines again if no columns are given
+func (pager *Pager) SetColumns(columns ...string) {
+	pager.sorted = nil
+	pager.table = nil
+	if len(columns) > 0 {
+		pager.table = &table{columns: columns}
+	}
+	pager.Rebuild()
+	pager.render()
+}
+
+// Columns returns the columns of the table
+// (nil while the page is rendered as lines)
+func (pager *Pager) Columns() []string {
+	if pager.table == nil {
+		return nil
+	}
+	return pager.table.columns
+}
+
+// SortBy pauses the pager and shows all buffered items ordered
+// by the column. Scrolling moves through the sorted items until
+// the pager is resumed. An empty column shows the items in the
+// order they have been received again.
+func (pager *Pager) SortBy(column string, descending bool) error {
+	if pager.table == nil {
+		return fmt.Errorf("sorting requires the table view")
+	}
+
+	pager.table.sortBy, pager.table.descending = column, descending
+	if column == "" {
+		pager.sorted = nil
+		pager.render()
+		return nil
+	}
+
+	oldest, latest, ok := pager.reader.Window()
+	if !ok {
+		return fmt.Errorf("no logs received yet")
+	}
+
+	sorted := make([]uint32, 0, latest-oldest+1)
+	for offset := oldest; offset <= latest && offset >= oldest; offset++ {
+		item := pager.reader.At(offset)
+		if item.Evicted || pager.skips(item) {
+			continue
+		}
+		sorted = append(sorted, offset)
+	}
+	pager.table.sort(pager.reader, sorted)
+
+	pager.paused = true
+	pager.sorted = sorted
+	pager.sortTop = 0
+	pager.renderSorted()
+	return nil
+}
+
+func (pager *Pager) renderSorted() {
+	end := pager.sortTop + int(pager.size)
+	if end > len(pager.sorted) {
+		end = len(pager.sorted)
+	}
+
+	offsets := make([]int, 0, end-pager.sortTop)
+	for _, offset := range pager.sorted[pager.sortTop:end] {
+		offsets = append(offsets, int(offset))
+	}
+	pager.renderTable(offsets, true)
+}
+
+// renderTable builds the bufferView from the items of the
+// offsets (one per line). The header takes the first line
+// such that on a full page either the last item is left out
+// (keepFirst) or the first one.
+func (pager *Pager) renderTable(offsets []int, keepFirst bool) {
+
+	var items []ring.Item
+	var rows []int
+	for _, offset := range offsets {
+		if offset == noItem {
+			continue
+		}
+		items = append(items, pager.reader.At(uint32(offset)))
+		rows = append(rows, offset)
+	}
+
+	if len(rows) >= int(pager.size) && pager.size > 0 {
+		drop := len(rows) - int(pager.size) + 1
+		if keepFirst {
+			items, rows = items[:len(items)-drop], rows[:len(rows)-drop]
+		} else {
+			items, rows = items[drop:], rows[drop:]
+		}
+	}
+
+	lines := pager.table.render(pager.prefix, items, pager.ttyWidth)
+	// empty rows keep the page at its size
+	for len(lines) < int(pager.size) {
+		lines = append(lines, "\000")
+	}
+	pager.bufferView = strings.Join(lines, "\n")
+	pager.viewOffsets = append([]int{noItem}, rows...)
+}
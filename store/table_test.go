@@ -0,0 +1,75 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTableRender(t *testing.T) {
+
+	store := New(12)
+	pager := store.NewPager(5, 60, testRefreshRate)
+
+	logs := []string{
+		`{"level":"info","msg":"served","latency":120}`,
+		`level=warn msg="slow request" latency=980`,
+		`{"level":"info","msg":"no latency"}`,
+		`{"level":"error","msg":"a message much too long to fit the column","latency":15}`,
+	}
+	for _, log := range logs {
+		store.Insert("api", time.Now(), []byte(log))
+		pager.MovePosition()
+	}
+	pager.SetColumns("level", "latency", "msg")
+
+	rows := strings.Split(pager.String(), "\n")
+	if len(rows) != 5 {
+		t.Fatalf("wanted header and 4 rows, got: %q", rows)
+	}
+	for i, want := range []string{
+		"api | info   120      served",
+		"api | warn   980      slow request",
+		"api | info            no latency",
+		"api | error  15       a message much too long to fit the co…",
+	} {
+		if rows[i+1] != want {
+			t.Fatalf("row %d: wanted:\n%q\ngot:\n%q", i+1, want, rows[i+1])
+		}
+	}
+	if offset, ok := pager.At(0); ok {
+		t.Fatalf("wanted the header to show no item, got offset: %d", offset)
+	}
+
+	if err := pager.SortBy("latency", true); err != nil {
+		t.Fatalf("unable to sort: %v", err)
+	}
+
+	var order []uint32
+	for row := 1; row < 5; row++ {
+		offset, _ := pager.At(row)
+		order = append(order, offset)
+	}
+	// numbers are compared by value and
+	// logs without the field are last
+	if want := []uint32{1, 0, 3, 2}; !equalOffsets(order, want) {
+		t.Fatalf("wanted sorted offsets %v, got: %v", want, order)
+	}
+
+	pager.SetColumns()
+	if !strings.HasPrefix(pager.String(), `api | {"level":"info"`) {
+		t.Fatalf("wanted lines again after clearing the columns, got:\n%s", pager.String())
+	}
+}
+
+func equalOffsets(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+}
+
+// logTime looks for the first of the fields in the data and
+// parses its value as time. JSON objects and logfmt lines are
+// supported. A zero time is returned if none of the fields is
+// present or the value can not be parsed.
+func logTime(data string, fields []string) time.Time {
+
+	if len(fields) == 0 {
+		return time.Time{}
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &obj); err != nil {
+			return time.Time{}
+		}
+
+		for _, field := range fields {
+			switch v := obj[field].(type) {
+			case float64:
+				return unixTime(v)
+			case string:
+				if t, ok := parseTime(v); ok {
+					return t
+				}
+			}
+		}
+		return time.Time{}
+	}
+
+	for _, field := range fields {
+		match := logfmtValue(field).FindStringSubmatch(data)
+		if match == nil {
+			continue
+		}
+
+		value := match[1]
+		if value == "" {
+			value = match[2]
+		}
+
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return unixTime(f)
+		}
+		if t, ok := parseTime(value); ok {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+var logfmtPatterns = map[string]*regexp.Regexp{}
+
+func logfmtValue(field string) *regexp.Regexp {
+	if re, ok := logfmtPatterns[field]; ok {
+		return re
+	}
+
+	// group 1 holds a quoted value, group 2 an unquoted one
+	re := regexp.MustCompile(`(?:^|\s)` + regexp.QuoteMeta(field) + `=(?:"([^"]*)"|(\S+))`)
+	logfmtPatterns[field] = re
+	return re
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Local(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// unixTime converts a unix timestamp into a time. The unit
+// (seconds, milli-, micro- or nanoseconds) is guessed from
+// the magnitude of the value.
+func unixTime(v float64) time.Time {
+	switch {
+	case v > 1e18:
+		return time.Unix(0, int64(v))
+	case v > 1e15:
+		return time.UnixMicro(int64(v))
+	case v > 1e12:
+		return time.UnixMilli(int64(v))
+	default:
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9))
+	}
+}
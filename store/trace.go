@@ -0,0 +1,225 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KonstantinGasser/scotty/store/ring"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TraceFields are the fields a request is followed by
+// across beams unless configured otherwise
+var TraceFields = []string{"trace_id", "request_id", "correlation_id"}
+
+var (
+	// arrayIndex matches the index of an array
+	// element of a JSONPath ([0])
+	arrayIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+	traceInfoStyle   = lipgloss.NewStyle().Faint(true)
+	traceDeltaStyle  = lipgloss.NewStyle().Faint(true)
+	traceCursorStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// Hop is a log of a trace
+type Hop struct {
+	Offset uint32
+	Label  string
+	Time   time.Time
+	// Delta is the time passed since the previous
+	// hop of the trace (zero for the first hop)
+	Delta time.Duration
+	Raw   string
+}
+
+// Trace follows the logs of every beam having the same value
+// in a field (such as a trace_id) in the order they arrived
+type Trace struct {
+	Field, Value string
+	hops         []Hop
+	reader       ring.Reader
+	prefix       *prefixer
+	// next is the offset of the next
+	// log to look at
+	next   uint32
+	cursor int
+	// top is the first hop shown
+	top int
+}
+
+// NewTrace collects the logs of the buffer having the
+// value in the field. Call Update to add logs received
+// afterwards.
+func (store *Store) NewTrace(field string, value string) *Trace {
+	trace := &Trace{
+		Field:  field,
+		Value:  value,
+		reader: store.buffer,
+		prefix: store.prefix,
+	}
+	trace.Update()
+	return trace
+}
+
+// Update adds the logs received since the last update
+// having the value in the field. It reports whether
+// any log has been added.
+func (trace *Trace) Update() bool {
+	oldest, latest, ok := trace.reader.Window()
+	if !ok {
+		return false
+	}
+	if trace.next < oldest {
+		trace.next = oldest
+	}
+
+	var added bool
+	for ; trace.next <= latest; trace.next++ {
+		item := trace.reader.At(trace.next)
+		if item.Evicted || item.Index() != trace.next+1 {
+			continue
+		}
+		if value, ok := fieldValue(item.Raw, trace.Field); !ok || value != trace.Value {
+			continue
+		}
+
+		hop := Hop{
+			Offset: trace.next,
+			Label:  item.Label,
+			Time:   item.Time(),
+			Raw:    item.Raw,
+		}
+		if n := len(trace.hops); n > 0 {
+			hop.Delta = hop.Time.Sub(trace.hops[n-1].Time)
+		}
+		trace.hops = append(trace.hops, hop)
+		added = true
+	}
+	return added
+}
+
+// Hops returns the logs of the trace in arrival order
+func (trace *Trace) Hops() []Hop {
+	return append([]Hop(nil), trace.hops...)
+}
+
+// Len returns the number of hops
+func (trace *Trace) Len() int {
+	return len(trace.hops)
+}
+
+// Move moves the cursor by delta hops
+func (trace *Trace) Move(delta int) {
+	trace.cursor += delta
+	if trace.cursor >= len(trace.hops) {
+		trace.cursor = len(trace.hops) - 1
+	}
+	if trace.cursor < 0 {
+		trace.cursor = 0
+	}
+}
+
+// Selected returns the hop at the cursor
+func (trace *Trace) Selected() (Hop, bool) {
+	if len(trace.hops) == 0 {
+		return Hop{}, false
+	}
+	return trace.hops[trace.cursor], true
+}
+
+// Overwritten reports whether the buffer no
+// longer holds the log of the hop
+func (trace *Trace) Overwritten(hop Hop) bool {
+	item := trace.reader.At(hop.Offset)
+	return item.Evicted || item.Index() != hop.Offset+1
+}
+
+// String returns a summary of the trace: the number of
+// hops and beams and the time from the first to the last hop
+func (trace *Trace) String() string {
+	beams := map[string]bool{}
+	for _, hop := range trace.hops {
+		beams[hop.Label] = true
+	}
+
+	var span time.Duration
+	if n := len(trace.hops); n > 1 {
+		span = trace.hops[n-1].Time.Sub(trace.hops[0].Time)
+	}
+	return fmt.Sprintf("%s=%s · %d hops · %d beams · %.3fs", trace.Field, trace.Value, len(trace.hops), len(beams), span.Seconds())
+}
+
+// Render returns the summary and a line per hop annotated with
+// the time passed since the previous hop and its beam. Hops
+// the buffer no longer holds show the log as traced.
+func (trace *Trace) Render(width int, height int) string {
+	lines := []string{traceInfoStyle.Render(fit(trace.String(), width))}
+	if len(trace.hops) == 0 {
+		return strings.Join(append(lines, traceInfoStyle.Render("no logs found")), "\n")
+	}
+
+	rows := height - 1
+	if rows < 1 {
+		rows = 1
+	}
+	if trace.cursor < trace.top {
+		trace.top = trace.cursor
+	}
+	if trace.cursor >= trace.top+rows {
+		trace.top = trace.cursor - rows + 1
+	}
+
+	for i := trace.top; i < len(trace.hops) && i < trace.top+rows; i++ {
+		hop := trace.hops[i]
+
+		delta := hop.Time.Format(absoluteLayout)
+		if i > 0 {
+			delta = fmt.Sprintf("%+11.3fs", hop.Delta.Seconds())
+		}
+		data := strings.ReplaceAll(hop.Raw, "\n", " ")
+		if trace.Overwritten(hop) {
+			data = evictedStyle.Render(data)
+		}
+
+		line := fit(traceDeltaStyle.Render(fmt.Sprintf("%12s", delta))+" "+trace.prefix.label(hop.Label)+divider+data, width)
+		if i == trace.cursor {
+			line = traceCursorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TraceValue returns the field and value the selected log can be
+// traced by. With the tree open the selected value is used, else
+// the first of the fields found in the log.
+func (formatter *Formatter) TraceValue(fields ...string) (string, string, error) {
+	item := formatter.reader.At(formatter.absolute)
+	if item.Index() == 0 || item.Evicted {
+		return "", "", fmt.Errorf("log %d cannot be traced", formatter.absolute)
+	}
+
+	if formatter.tree != nil {
+		selected := formatter.tree.selected()
+		if selected.container || !strings.HasPrefix(selected.path, "$.") {
+			return "", "", fmt.Errorf("select a value of the tree to trace")
+		}
+		// $.user.roles[0] is looked up as user.roles.0
+		field := arrayIndex.ReplaceAllString(strings.TrimPrefix(selected.path, "$."), ".$1")
+		value, err := lookupField(item.Raw, field)
+		if err != nil {
+			return "", "", err
+		}
+		return field, value, nil
+	}
+
+	for _, field := range fields {
+		if value, ok := fieldValue(item.Raw, field); ok && value != "" {
+			return field, value, nil
+		}
+	}
+	return "", "", fmt.Errorf("log has none of the fields %s", strings.Join(fields, ", "))
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+
+	store := New(8)
+	start := time.Now()
+
+	store.Insert("gateway", start, []byte(`{"msg":"request","trace_id":"abc"}`))
+	store.Insert("gateway", start.Add(5*time.Millisecond), []byte(`{"msg":"request","trace_id":"xyz"}`))
+	store.Insert("users", start.Add(20*time.Millisecond), []byte(`level=info msg=lookup trace_id=abc`))
+
+	trace := store.NewTrace("trace_id", "abc")
+	store.Insert("db", start.Add(50*time.Millisecond), []byte(`{"query":"select","trace_id":"abc"}`))
+	if !trace.Update() {
+		t.Fatalf("wanted the log received after the trace started to be added")
+	}
+	if trace.Update() {
+		t.Fatalf("wanted no log to be added twice")
+	}
+
+	hops := trace.Hops()
+	var labels []string
+	for _, hop := range hops {
+		labels = append(labels, hop.Label)
+	}
+	if strings.Join(labels, " ") != "gateway users db" {
+		t.Fatalf("wanted the hops gateway users db, got: %v", labels)
+	}
+	if hops[0].Delta != 0 || hops[1].Delta != 20*time.Millisecond || hops[2].Delta != 30*time.Millisecond {
+		t.Fatalf("wanted the time passed since the previous hop, got: %v %v %v", hops[0].Delta, hops[1].Delta, hops[2].Delta)
+	}
+	if !strings.Contains(trace.String(), "3 hops · 3 beams · 0.050s") {
+		t.Fatalf("wanted the summary of the trace, got: %s", trace.String())
+	}
+	if view := trace.Render(80, 10); !strings.Contains(view, "+0.030s") {
+		t.Fatalf("wanted the delta of the db hop, got:\n%s", view)
+	}
+}
+
+func TestTraceValue(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(12, 80)
+
+	store.Insert("api", time.Now(), []byte(`{"request_id":"r-1","user":{"session":"s-9"}}`))
+	store.Insert("api", time.Now(), []byte(`level=info msg=done`))
+	formatter.Load(0)
+
+	field, value, err := formatter.TraceValue(TraceFields...)
+	if err != nil || field != "request_id" || value != "r-1" {
+		t.Fatalf("wanted request_id=r-1, got: %s=%s (%v)", field, value, err)
+	}
+
+	// the value selected in the tree wins
+	formatter.Explore()
+	if err := formatter.TreeFind("session"); err != nil {
+		t.Fatalf("unable to find the key: %v", err)
+	}
+	field, value, err = formatter.TraceValue(TraceFields...)
+	if err != nil || field != "user.session" || value != "s-9" {
+		t.Fatalf("wanted user.session=s-9, got: %s=%s (%v)", field, value, err)
+	}
+	formatter.Explore()
+
+	formatter.Next()
+	if _, _, err := formatter.TraceValue(TraceFields...); err == nil {
+		t.Fatalf("wanted an error for a log without trace field")
+	}
+}
@@ -0,0 +1,437 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// node is an object, array or value of a JSON log
+type node struct {
+	key string
+	// path of the node as JSONPath ($.user.roles[0])
+	path     string
+	depth    int
+	children []*node
+	// container reports whether the node is an
+	// object or array (brackets are "{}" or "[]")
+	container bool
+	brackets  string
+	// value of a scalar as written in the log
+	value string
+}
+
+// tree explores a JSON log with objects and
+// arrays which can be expanded and collapsed
+type tree struct {
+	root *node
+	// index of the item the tree is built from
+	index uint32
+	// expanded nodes by path
+	expanded map[string]bool
+	// rows are the visible nodes top to bottom
+	rows   []*node
+	cursor int
+	// top is the first row shown in the modal
+	top int
+}
+
+var (
+	identifier = regexp.MustCompile(`^[A-Za-z_][\w]*$`)
+
+	treeCursorStyle = lipgloss.NewStyle().Reverse(true)
+	treePinStyle    = lipgloss.NewStyle().Bold(true)
+	treeInfoStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+// parseTree parses the data into nodes keeping the
+// order of the keys as written in the log
+func parseTree(data string) (*node, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+
+	root, err := parseNode(decoder, "", "$", 0)
+	if err != nil {
+		return nil, fmt.Errorf("log is not JSON")
+	}
+	if !root.container {
+		return nil, fmt.Errorf("log is not a JSON object or array")
+	}
+	return root, nil
+}
+
+func parseNode(decoder *json.Decoder, key string, path string, depth int) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &node{key: key, path: path, depth: depth}
+	switch token := token.(type) {
+	case json.Delim:
+		n.container = true
+		n.brackets = "{}"
+		if token == '[' {
+			n.brackets = "[]"
+		}
+		for i := 0; decoder.More(); i++ {
+			childKey, childPath := "", ""
+			if token == '{' {
+				name, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				childKey = name.(string)
+				childPath = path + "." + childKey
+				if !identifier.MatchString(childKey) {
+					childPath = path + "['" + strings.ReplaceAll(childKey, "'", `\'`) + "']"
+				}
+			} else {
+				childKey = strconv.Itoa(i)
+				childPath = path + "[" + childKey + "]"
+			}
+			child, err := parseNode(decoder, childKey, childPath, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+		// closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		quoted, _ := json.Marshal(token)
+		n.value = string(quoted)
+	case nil:
+		n.value = "null"
+	default:
+		n.value = fmt.Sprint(token)
+	}
+
+	if depth == 0 {
+		if _, err := decoder.Token(); err != io.EOF {
+			return nil, fmt.Errorf("trailing data")
+		}
+	}
+	return n, nil
+}
+
+// newTree shows the top level of the root expanded as
+// well as the pinned paths. The cursor is placed on the
+// node of the path if the log has it.
+func newTree(root *node, pinned map[string]bool, path string) *tree {
+	t := &tree{root: root, expanded: map[string]bool{root.path: true}}
+	for pin := range pinned {
+		t.expandTo(pin)
+		t.expanded[pin] = true
+	}
+	t.expandTo(path)
+	t.flatten()
+	for i, row := range t.rows {
+		if row.path == path {
+			t.cursor = i
+		}
+	}
+	return t
+}
+
+// flatten collects the visible nodes
+func (t *tree) flatten() {
+	t.rows = t.rows[:0]
+	var walk func(n *node)
+	walk = func(n *node) {
+		t.rows = append(t.rows, n)
+		if !t.expanded[n.path] {
+			return
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(t.root)
+
+	if t.cursor >= len(t.rows) {
+		t.cursor = len(t.rows) - 1
+	}
+}
+
+// expandTo expands all parents of the node at the path
+func (t *tree) expandTo(path string) {
+	var walk func(n *node) bool
+	walk = func(n *node) bool {
+		if n.path == path {
+			return true
+		}
+		for _, child := range n.children {
+			if walk(child) {
+				t.expanded[n.path] = true
+				return true
+			}
+		}
+		return false
+	}
+	walk(t.root)
+}
+
+func (t *tree) selected() *node {
+	return t.rows[t.cursor]
+}
+
+func (t *tree) move(delta int) {
+	t.cursor += delta
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+	if t.cursor >= len(t.rows) {
+		t.cursor = len(t.rows) - 1
+	}
+}
+
+// toggle expands or collapses the selected node
+func (t *tree) toggle() {
+	n := t.selected()
+	if !n.container || n == t.root {
+		return
+	}
+	t.expanded[n.path] = !t.expanded[n.path]
+	t.flatten()
+}
+
+// expandAll expands (or collapses) all nodes
+// below the selected one. Collapsing keeps
+// the selected node expanded.
+func (t *tree) expandAll(expand bool) {
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.container {
+			t.expanded[n.path] = expand
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	selected := t.selected()
+	walk(selected)
+	t.expanded[selected.path] = true
+	t.expanded[t.root.path] = true
+	t.flatten()
+}
+
+// find selects the next node after the selected one whose
+// key contains the text (case insensitive) and expands
+// its parents
+func (t *tree) find(text string) error {
+	text = strings.ToLower(text)
+
+	var all []*node
+	var walk func(n *node)
+	walk = func(n *node) {
+		all = append(all, n)
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(t.root)
+
+	start := 0
+	for i, n := range all {
+		if n == t.selected() {
+			start = i
+		}
+	}
+	for i := 1; i <= len(all); i++ {
+		n := all[(start+i)%len(all)]
+		if n == t.root || !strings.Contains(strings.ToLower(n.key), text) {
+			continue
+		}
+		t.expandTo(n.path)
+		t.flatten()
+		for row, visible := range t.rows {
+			if visible == n {
+				t.cursor = row
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("no key matching %q", text)
+}
+
+// render returns the rows fitting the height with the
+// selected row visible. Pinned rows are marked.
+func (t *tree) render(width int, height int, pinned map[string]bool) string {
+	if height < 1 {
+		height = 1
+	}
+	if t.cursor < t.top {
+		t.top = t.cursor
+	}
+	if t.cursor >= t.top+height {
+		t.top = t.cursor - height + 1
+	}
+	if t.top > 0 && t.top+height > len(t.rows) {
+		t.top = len(t.rows) - height
+		if t.top < 0 {
+			t.top = 0
+		}
+	}
+
+	lines := make([]string, 0, height)
+	for i := t.top; i < len(t.rows) && i < t.top+height; i++ {
+		n := t.rows[i]
+
+		marker := "  "
+		if n.container {
+			marker = "▸ "
+			if t.expanded[n.path] {
+				marker = "▾ "
+			}
+		}
+		if pinned[n.path] {
+			marker += "● "
+		}
+
+		line := strings.Repeat("  ", n.depth) + marker
+		if n != t.root {
+			line += n.key + ": "
+		}
+		switch {
+		case !n.container:
+			line += n.value
+		case t.expanded[n.path]:
+			line += n.brackets[:1]
+		default:
+			line += fmt.Sprintf("%s…%s %d", n.brackets[:1], n.brackets[1:], len(n.children))
+		}
+
+		line = fit(line, width)
+		if i == t.cursor {
+			line = treeCursorStyle.Render(line)
+		} else if pinned[n.path] {
+			line = treePinStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// info shows the path of the selected node
+// and its position within the visible rows
+func (t *tree) info(width int) string {
+	return treeInfoStyle.Render(fit(fmt.Sprintf("%s  (%d/%d)", t.selected().path, t.cursor+1, len(t.rows)), width))
+}
+
+// Explore opens the tree of the selected log replacing the
+// pretty printed log in the modal or closes the tree again.
+// Logs selected while the tree is open are shown as trees.
+func (formatter *Formatter) Explore() error {
+	if formatter.tree != nil {
+		formatter.tree = nil
+		formatter.buildForeground()
+		return nil
+	}
+
+	if err := formatter.buildTree(""); err != nil {
+		return err
+	}
+	formatter.buildForeground()
+	return nil
+}
+
+// Exploring reports whether the tree is open
+func (formatter *Formatter) Exploring() bool {
+	return formatter.tree != nil
+}
+
+// buildTree builds the tree of the selected log with the
+// pinned paths expanded and the node of the path selected
+func (formatter *Formatter) buildTree(path string) error {
+	item := formatter.reader.At(formatter.absolute)
+	if item.Evicted {
+		return fmt.Errorf("log %d has been evicted", formatter.absolute)
+	}
+
+	root, err := parseTree(item.Raw)
+	if err != nil {
+		return err
+	}
+	formatter.tree = newTree(root, formatter.pinned, path)
+	formatter.tree.index = formatter.absolute
+	return nil
+}
+
+// TreeMove moves the selected node of the tree by delta rows
+func (formatter *Formatter) TreeMove(delta int) {
+	if formatter.tree == nil {
+		return
+	}
+	formatter.tree.move(delta)
+	formatter.buildForeground()
+}
+
+// TreeToggle expands or collapses the selected node
+func (formatter *Formatter) TreeToggle() {
+	if formatter.tree == nil {
+		return
+	}
+	formatter.tree.toggle()
+	formatter.buildForeground()
+}
+
+// TreeExpand expands (or collapses) all
+// nodes below the selected node
+func (formatter *Formatter) TreeExpand(expand bool) {
+	if formatter.tree == nil {
+		return
+	}
+	formatter.tree.expandAll(expand)
+	formatter.buildForeground()
+}
+
+// TreeFind selects the next node with a key containing the text
+func (formatter *Formatter) TreeFind(text string) error {
+	if formatter.tree == nil {
+		return fmt.Errorf("open the tree first")
+	}
+	if err := formatter.tree.find(text); err != nil {
+		return err
+	}
+	formatter.buildForeground()
+	return nil
+}
+
+// TreePin pins the selected node (or unpins it). Pinned nodes
+// are shown expanded in the tree of every log having them.
+func (formatter *Formatter) TreePin() {
+	if formatter.tree == nil {
+		return
+	}
+	path := formatter.tree.selected().path
+	if formatter.pinned[path] {
+		delete(formatter.pinned, path)
+	} else {
+		formatter.pinned[path] = true
+		formatter.tree.expanded[path] = true
+		formatter.tree.flatten()
+	}
+	formatter.buildForeground()
+}
+
+// TreePath returns the JSONPath of the selected node
+func (formatter *Formatter) TreePath() (string, error) {
+	if formatter.tree == nil {
+		return "", fmt.Errorf("open the tree first")
+	}
+	return formatter.tree.selected().path, nil
+}
+
+// treeHeight is the number of rows of the tree fitting
+// the page next to the borders, prefix and path of the modal
+func (formatter *Formatter) treeHeight() int {
+	return int(formatter.size) - 4
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTree(t *testing.T) {
+
+	root, err := parseTree(`{"msg":"done","user":{"id":42,"roles":["admin","dev"]},"a b":null}`)
+	if err != nil {
+		t.Fatalf("unable to parse the tree: %v", err)
+	}
+
+	var paths []string
+	var walk func(n *node)
+	walk = func(n *node) {
+		paths = append(paths, n.path)
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	// keys are kept in the order of the log
+	want := []string{"$", "$.msg", "$.user", "$.user.id", "$.user.roles", "$.user.roles[0]", "$.user.roles[1]", "$['a b']"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("wanted paths:\n%v\ngot:\n%v", want, paths)
+	}
+
+	for _, data := range []string{`level=info`, `"text"`, `{"a":1} trailing`} {
+		if _, err := parseTree(data); err == nil {
+			t.Fatalf("wanted an error for %q", data)
+		}
+	}
+}
+
+func TestTreeExplore(t *testing.T) {
+
+	store := New(4)
+	formatter := store.NewFormatter(12, 60)
+
+	store.Insert("api", time.Now(), []byte(`{"msg":"one","user":{"id":1,"roles":["admin"]},"tags":["a","b"]}`))
+	store.Insert("api", time.Now(), []byte(`{"msg":"two","user":{"id":2,"roles":[]}}`))
+	formatter.Load(0)
+
+	if err := formatter.Explore(); err != nil {
+		t.Fatalf("unable to open the tree: %v", err)
+	}
+
+	// only the top level is expanded
+	if got := len(formatter.tree.rows); got != 4 {
+		t.Fatalf("wanted 4 visible rows, got: %d", got)
+	}
+
+	if err := formatter.TreeFind("ROLES"); err != nil {
+		t.Fatalf("unable to find the key: %v", err)
+	}
+	if path, _ := formatter.TreePath(); path != "$.user.roles" {
+		t.Fatalf("wanted $.user.roles to be selected, got: %s", path)
+	}
+	if err := formatter.TreeFind("missing"); err == nil {
+		t.Fatalf("wanted an error for a key not in the log")
+	}
+
+	formatter.TreePin()
+	formatter.Next()
+
+	// the pinned roles of the next log are
+	// expanded and stay selected
+	if path, _ := formatter.TreePath(); path != "$.user.roles" {
+		t.Fatalf("wanted $.user.roles to stay selected, got: %s", path)
+	}
+	if !strings.Contains(formatter.foreground, `msg: "two"`) {
+		t.Fatalf("wanted the tree of the next log, got:\n%s", formatter.foreground)
+	}
+
+	formatter.TreeMove(-2)
+	formatter.TreeToggle()
+	if formatter.tree.expanded["$.user"] {
+		t.Fatalf("wanted $.user to be collapsed")
+	}
+
+	if err := formatter.Explore(); err != nil || formatter.Exploring() {
+		t.Fatalf("wanted the tree to be closed, got: %v", err)
+	}
+}
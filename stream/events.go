@@ -1,13 +1,19 @@
 package stream
 
+import "time"
+
 // any error captured while
 // adding/reading from a stream
 type Error error
 
 // any new stream which is connecting
 // to scotty represented by a name provided
-// via beam -label
-type Subscriber string
+// via beam -label. Color is optional and set
+// if the beam requested a color in the SYNC message
+type Subscriber struct {
+	Label string
+	Color string
+}
 
 // any stream returning an io.EOF therefore
 // closing the connection or any other reason
@@ -18,5 +24,8 @@ type Unsubscribe string
 // stream
 type Message struct {
 	Label string
-	Data  []byte
+	// time the message was read from
+	// the stream
+	Received time.Time
+	Data     []byte
 }
@@ -39,14 +39,30 @@ type Listener struct {
 	// and the event is propagated using this channel
 	unsubscribe chan Unsubscribe
 
-	listener net.Listener
+	listeners []net.Listener
 }
 
-func New(q <-chan struct{}, network string, addr string) (*Listener, error) {
+// Address is a network/address pair scotty
+// listens on for connecting beams
+type Address struct {
+	Network string
+	Addr    string
+}
+
+func New(q <-chan struct{}, addrs ...Address) (*Listener, error) {
 
-	ln, err := net.Listen(network, addr)
-	if err != nil {
-		return nil, fmt.Errorf("unable to start scotty with this network/addrr configurations.\n Make sure no other instance is running on this network/addrr.\nPlease see also the exact network error:\n\t:%v", err)
+	var listeners []net.Listener
+	for _, addr := range addrs {
+		ln, err := net.Listen(addr.Network, addr.Addr)
+		if err != nil {
+			// release already opened listeners else the unix
+			// sockets are left behind
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("unable to start scotty with this network/addrr configurations (%s %s).\n Make sure no other instance is running on this network/addrr.\nPlease see also the exact network error:\n\t:%v", addr.Network, addr.Addr, err)
+		}
+		listeners = append(listeners, ln)
 	}
 
 	return &Listener{
@@ -56,19 +72,35 @@ func New(q <-chan struct{}, network string, addr string) (*Listener, error) {
 		subscribe:   make(chan Subscriber),
 		subscribers: make(map[string]struct{}),
 		unsubscribe: make(chan Unsubscribe),
-		listener:    ln,
+		listeners:   listeners,
 	}, nil
 }
 
+// Run accepts connections on all configured listeners
+// and blocks until scotty is shutting down
 func (ln *Listener) Run() {
 
 	go func() {
 		<-ln.quite
-		ln.listener.Close()
+		for _, l := range ln.listeners {
+			l.Close()
+		}
 	}()
 
+	var wg sync.WaitGroup
+	for _, l := range ln.listeners {
+		wg.Add(1)
+		go func(l net.Listener) {
+			defer wg.Done()
+			ln.accept(l)
+		}(l)
+	}
+	wg.Wait()
+}
+
+func (ln *Listener) accept(listener net.Listener) {
 	for {
-		conn, err := ln.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
 			// call to quite lead to closing of listener
 			// scotty is shutting down, break
@@ -101,7 +133,7 @@ func (ln *Listener) Run() {
 				ln.mtx.Unlock()
 
 			}
-			ln.subscribe <- Subscriber(s.label)
+			ln.subscribe <- Subscriber{Label: s.label, Color: s.color}
 
 			// blocking operation until error or EOF of client
 			if err := s.handle(); err != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 )
 
 var (
@@ -14,6 +15,7 @@ var (
 
 type stream struct {
 	label  string
+	color  string
 	msgs   chan<- Message
 	reader net.Conn
 }
@@ -63,8 +65,9 @@ func (s *stream) handle() error {
 		}
 
 		s.msgs <- Message{
-			Label: s.label,
-			Data:  msg,
+			Label:    s.label,
+			Received: time.Now(),
+			Data:     msg,
 		}
 	}
 	// if we reach this line the EOF broke the look and it is safe
@@ -92,6 +95,9 @@ func (s *stream) waitForSync() error {
 	type (
 		metadata struct {
 			Label string `json:"label"`
+			// optional color of the beam
+			// (#rrggbb or 0-255)
+			Color string `json:"color"`
 		}
 	)
 
@@ -101,5 +107,6 @@ func (s *stream) waitForSync() error {
 	}
 
 	s.label = meta.Label
+	s.color = meta.Color
 	return nil
 }